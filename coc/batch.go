@@ -0,0 +1,92 @@
+package coc
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// defaultConcurrency is the number of requests sent at the same time by a batch
+	defaultConcurrency = 10
+)
+
+// Result is the result of fetching a single tag in a batch. Either Value or Err is set.
+type Result[T any] struct {
+	Tag   string
+	Value *T
+	Err   error
+}
+
+// GetPlayers retrieves the players with the given tags concurrently. A result is
+// returned for each tag, in the same order as the tags; a failure to retrieve one
+// player does not stop the others from being retrieved.
+func GetPlayers(ctx context.Context, tags []string) []Result[Player] {
+	return defaultClient.GetPlayers(ctx, tags)
+}
+
+// GetClansByTag retrieves the clans with the given tags concurrently. A result is
+// returned for each tag, in the same order as the tags; a failure to retrieve one
+// clan does not stop the others from being retrieved.
+func GetClansByTag(ctx context.Context, tags []string) []Result[Clan] {
+	return defaultClient.GetClansByTag(ctx, tags)
+}
+
+// GetPlayers retrieves the players with the given tags concurrently. A result is
+// returned for each tag, in the same order as the tags; a failure to retrieve one
+// player does not stop the others from being retrieved.
+func (c *Client) GetPlayers(ctx context.Context, tags []string) []Result[Player] {
+	ctx, span := c.tracer.Start(ctx, "coc.GetPlayers", trace.WithAttributes(attribute.Int("coc.batch_size", len(tags))))
+	defer span.End()
+
+	return fetchAll(ctx, c.concurrency, tags, c.GetPlayerContext)
+}
+
+// GetClansByTag retrieves the clans with the given tags concurrently. A result is
+// returned for each tag, in the same order as the tags; a failure to retrieve one
+// clan does not stop the others from being retrieved.
+func (c *Client) GetClansByTag(ctx context.Context, tags []string) []Result[Clan] {
+	ctx, span := c.tracer.Start(ctx, "coc.GetClansByTag", trace.WithAttributes(attribute.Int("coc.batch_size", len(tags))))
+	defer span.End()
+
+	return fetchAll(ctx, c.concurrency, tags, c.GetClanContext)
+}
+
+// fetchAll fetches each tag using a pool of workers, so that no more than concurrency
+// requests are in progress at once. Requests still go through the client's rate
+// limiter, so the pool never sends requests faster than the limiter allows.
+func fetchAll[T any](ctx context.Context, concurrency int, tags []string, fetch func(context.Context, string) (*T, error)) []Result[T] {
+	results := make([]Result[T], len(tags))
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > len(tags) {
+		concurrency = len(tags)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i].Tag = tags[i]
+				if err := ctx.Err(); err != nil {
+					results[i].Err = err
+					continue
+				}
+				results[i].Value, results[i].Err = fetch(ctx, tags[i])
+			}
+		}()
+	}
+	for i := range tags {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,117 @@
+package coc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/clashgolang/coc/pkg/rest"
+)
+
+// newBatchServer starts a server that holds each request for a while before returning
+// the player asked for, and records the most requests it was handling at once. The
+// player "#MISSING" is not found.
+func newBatchServer(t *testing.T, maxInFlight *atomic.Int32) *httptest.Server {
+	t.Helper()
+	var inFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			max := maxInFlight.Load()
+			if n <= max || maxInFlight.CompareAndSwap(max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		tag := strings.TrimPrefix(r.URL.Path, "/players/")
+		if tag == "#MISSING" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"reason":"notFound"}`))
+			return
+		}
+		fmt.Fprintf(w, `{"tag":%q,"name":"player"}`, tag)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetPlayers(t *testing.T) {
+	var maxInFlight atomic.Int32
+	server := newBatchServer(t, &maxInFlight)
+	client := NewClient("token", WithBaseURL(server.URL), WithConcurrency(3))
+
+	tags := []string{"#P0", "#P1", "#P2", "#MISSING", "#P4", "#P5", "#P6", "#P7", "#P8", "#P9"}
+	results := client.GetPlayers(context.Background(), tags)
+
+	if len(results) != len(tags) {
+		t.Fatalf("got %d results, want %d", len(results), len(tags))
+	}
+	for i, result := range results {
+		if result.Tag != tags[i] {
+			t.Errorf("result %d: got tag %q, want %q", i, result.Tag, tags[i])
+		}
+		if tags[i] == "#MISSING" {
+			if !errors.Is(result.Err, rest.ErrNotFound) || result.Value != nil {
+				t.Errorf("result %d: got (%v, %v), want ErrNotFound", i, result.Value, result.Err)
+			}
+			continue
+		}
+		if result.Err != nil {
+			t.Errorf("result %d: got %v, want the player", i, result.Err)
+			continue
+		}
+		if result.Value.Tag != tags[i] {
+			t.Errorf("result %d: got player %q, want %q", i, result.Value.Tag, tags[i])
+		}
+	}
+	if got := maxInFlight.Load(); got < 2 || got > 3 {
+		t.Errorf("server handled %d requests at once, want up to 3", got)
+	}
+}
+
+func TestGetPlayersCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The second request cancels the batch while it is in progress
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 2 {
+			cancel()
+			<-r.Context().Done()
+			return
+		}
+		fmt.Fprintf(w, `{"tag":%q,"name":"player"}`, strings.TrimPrefix(r.URL.Path, "/players/"))
+	}))
+	t.Cleanup(server.Close)
+	client := NewClient("token", WithBaseURL(server.URL), WithConcurrency(1))
+
+	tags := []string{"#P0", "#P1", "#P2", "#P3", "#P4"}
+	results := client.GetPlayers(ctx, tags)
+
+	if len(results) != len(tags) {
+		t.Fatalf("got %d results, want %d", len(results), len(tags))
+	}
+	if results[0].Err != nil {
+		t.Errorf("result 0: got %v, want the player fetched before the batch was canceled", results[0].Err)
+	}
+	for i, result := range results[1:] {
+		if result.Tag != tags[i+1] {
+			t.Errorf("result %d: got tag %q, want %q", i+1, result.Tag, tags[i+1])
+		}
+		if !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("result %d: got %v, want context.Canceled", i+1, result.Err)
+		}
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("server received %d requests, want 2", got)
+	}
+}
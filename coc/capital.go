@@ -0,0 +1,198 @@
+package coc
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// CapitalRaidSeason is a raid weekend in which a clan attacked other clans' capitals.
+type CapitalRaidSeason struct {
+	State                   string                    `json:"state"`
+	StartTime               CoCTime                   `json:"startTime"`
+	EndTime                 CoCTime                   `json:"endTime"`
+	CapitalTotalLoot        int                       `json:"capitalTotalLoot"`
+	RaidsCompleted          int                       `json:"raidsCompleted"`
+	TotalAttacks            int                       `json:"totalAttacks"`
+	EnemyDistrictsDestroyed int                       `json:"enemyDistrictsDestroyed"`
+	OffensiveReward         int                       `json:"offensiveReward"`
+	DefensiveReward         int                       `json:"defensiveReward"`
+	Members                 []CapitalRaidSeasonMember `json:"members,omitempty"`
+	AttackLog               []CapitalRaidLogEntry     `json:"attackLog,omitempty"`
+	DefenseLog              []CapitalRaidLogEntry     `json:"defenseLog,omitempty"`
+}
+
+// String returns a string representation of a capital raid season
+func (crs CapitalRaidSeason) String() string {
+	b, _ := json.Marshal(crs)
+	return string(b)
+}
+
+// CapitalRaidSeasonMember is a clan member who attacked during a raid weekend.
+type CapitalRaidSeasonMember struct {
+	Tag                    string `json:"tag"`
+	Name                   string `json:"name"`
+	Attacks                int    `json:"attacks"`
+	AttackLimit            int    `json:"attackLimit"`
+	BonusAttackLimit       int    `json:"bonusAttackLimit"`
+	CapitalResourcesLooted int    `json:"capitalResourcesLooted"`
+}
+
+// String returns a string representation of a capital raid season member
+func (crm CapitalRaidSeasonMember) String() string {
+	b, _ := json.Marshal(crm)
+	return string(b)
+}
+
+// CapitalRaidLogEntry is a raid on a single clan capital. In the attack log the
+// defender is set; in the defense log the attacker is set.
+type CapitalRaidLogEntry struct {
+	Attacker           *CapitalRaidClan      `json:"attacker,omitempty"`
+	Defender           *CapitalRaidClan      `json:"defender,omitempty"`
+	AttackCount        int                   `json:"attackCount"`
+	DistrictCount      int                   `json:"districtCount"`
+	DistrictsDestroyed int                   `json:"districtsDestroyed"`
+	Districts          []CapitalRaidDistrict `json:"districts,omitempty"`
+}
+
+// String returns a string representation of a capital raid log entry
+func (cre CapitalRaidLogEntry) String() string {
+	b, _ := json.Marshal(cre)
+	return string(b)
+}
+
+// CapitalRaidClan is the clan on the other side of a capital raid.
+type CapitalRaidClan struct {
+	Tag       string    `json:"tag"`
+	Name      string    `json:"name"`
+	Level     int       `json:"level"`
+	BadgeUrls BadgeUrls `json:"badgeUrls"`
+}
+
+// String returns a string representation of a capital raid clan
+func (crc CapitalRaidClan) String() string {
+	b, _ := json.Marshal(crc)
+	return string(b)
+}
+
+// CapitalRaidDistrict is a district of a clan capital that was attacked in a raid.
+type CapitalRaidDistrict struct {
+	ID                 int                 `json:"id"`
+	Name               string              `json:"name"`
+	DistrictHallLevel  int                 `json:"districtHallLevel"`
+	DestructionPercent int                 `json:"destructionPercent"`
+	Stars              int                 `json:"stars"`
+	AttackCount        int                 `json:"attackCount"`
+	TotalLooted        int                 `json:"totalLooted"`
+	Attacks            []CapitalRaidAttack `json:"attacks,omitempty"`
+}
+
+// String returns a string representation of a capital raid district
+func (crd CapitalRaidDistrict) String() string {
+	b, _ := json.Marshal(crd)
+	return string(b)
+}
+
+// CapitalRaidAttack is a single attack on a district of a clan capital.
+type CapitalRaidAttack struct {
+	Attacker           CapitalRaidAttacker `json:"attacker"`
+	DestructionPercent int                 `json:"destructionPercent"`
+	Stars              int                 `json:"stars"`
+}
+
+// String returns a string representation of a capital raid attack
+func (cra CapitalRaidAttack) String() string {
+	b, _ := json.Marshal(cra)
+	return string(b)
+}
+
+// CapitalRaidAttacker is the player who made an attack on a district.
+type CapitalRaidAttacker struct {
+	Tag  string `json:"tag"`
+	Name string `json:"name"`
+}
+
+// String returns a string representation of a capital raid attacker
+func (cra CapitalRaidAttacker) String() string {
+	b, _ := json.Marshal(cra)
+	return string(b)
+}
+
+// GetCapitalRaidSeasons returns the clan's capital raid seasons, most recent first
+func GetCapitalRaidSeasons(clanTag string, opts ...RequestOption) ([]CapitalRaidSeason, error) {
+	return defaultClient.GetCapitalRaidSeasons(clanTag, opts...)
+}
+
+// GetCapitalRaidSeasonsContext returns the clan's capital raid seasons, most recent first
+func GetCapitalRaidSeasonsContext(ctx context.Context, clanTag string, opts ...RequestOption) ([]CapitalRaidSeason, error) {
+	return defaultClient.GetCapitalRaidSeasonsContext(ctx, clanTag, opts...)
+}
+
+// GetCapitalRaidSeasonsPage returns a single page of capital raid seasons, along
+// with the cursors of the pages before and after it
+func GetCapitalRaidSeasonsPage(ctx context.Context, clanTag string, opts ...RequestOption) (*Page[CapitalRaidSeason], error) {
+	return defaultClient.GetCapitalRaidSeasonsPage(ctx, clanTag, opts...)
+}
+
+// IterateCapitalRaidSeasons returns an iterator over the capital raid seasons,
+// fetching pages as they are needed. At most maxItems items are returned; if
+// maxItems is zero, every item is returned.
+func IterateCapitalRaidSeasons(ctx context.Context, clanTag string, maxItems int, opts ...RequestOption) *Iterator[CapitalRaidSeason] {
+	return defaultClient.IterateCapitalRaidSeasons(ctx, clanTag, maxItems, opts...)
+}
+
+// GetCapitalRaidSeasons returns the clan's capital raid seasons, most recent first
+//
+// GetCapitalRaidSeasons uses context.Background internally; to specify the context, use
+// GetCapitalRaidSeasonsContext.
+func (c *Client) GetCapitalRaidSeasons(clanTag string, opts ...RequestOption) ([]CapitalRaidSeason, error) {
+	return c.GetCapitalRaidSeasonsContext(context.Background(), clanTag, opts...)
+}
+
+// GetCapitalRaidSeasonsContext returns the clan's capital raid seasons, most recent first
+func (c *Client) GetCapitalRaidSeasonsContext(ctx context.Context, clanTag string, opts ...RequestOption) ([]CapitalRaidSeason, error) {
+	page, err := c.GetCapitalRaidSeasonsPage(ctx, clanTag, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+// GetCapitalRaidSeasonsPage returns a single page of capital raid seasons, along
+// with the cursors of the pages before and after it
+func (c *Client) GetCapitalRaidSeasonsPage(ctx context.Context, clanTag string, opts ...RequestOption) (*Page[CapitalRaidSeason], error) {
+	ctx, span := c.startSpan(ctx, "GetCapitalRaidSeasons", "/clans/{clanTag}/capitalraidseasons", attribute.String("coc.tag", clanTag))
+	defer span.End()
+
+	var sb strings.Builder
+	sb.Grow(100)
+	sb.WriteString(c.baseURL)
+	sb.WriteString("/clans/")
+	sb.WriteString(fmtTag(clanTag))
+	sb.WriteString("/capitalraidseasons")
+	url := sb.String()
+
+	body, err := c.get(ctx, url, requestParms(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse into a page of capital raid seasons
+	var page Page[CapitalRaidSeason]
+	if err := json.Unmarshal(body, &page); err != nil {
+		c.log.Debug("failed to parse the json response", "error", err)
+		return nil, err
+	}
+	return &page, nil
+}
+
+// IterateCapitalRaidSeasons returns an iterator over the capital raid seasons,
+// fetching pages as they are needed. At most maxItems items are returned; if
+// maxItems is zero, every item is returned.
+func (c *Client) IterateCapitalRaidSeasons(ctx context.Context, clanTag string, maxItems int, opts ...RequestOption) *Iterator[CapitalRaidSeason] {
+	return newIterator(ctx, maxItems, opts, func(ctx context.Context, opts ...RequestOption) (*Page[CapitalRaidSeason], error) {
+		return c.GetCapitalRaidSeasonsPage(ctx, clanTag, opts...)
+	})
+}
@@ -1,14 +1,13 @@
 package coc
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"strings"
 
-	"github.com/clashgolang/coc/pkg/config"
 	"github.com/clashgolang/coc/pkg/rest"
-
-	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Clan is a clan in Clash of Clans.
@@ -92,6 +91,44 @@ func (l ClanVersusRanking) String() string {
 	return string(b)
 }
 
+// ClanBuilderBaseRanking is the clan builder base ranking for a specific location
+type ClanBuilderBaseRanking struct {
+	BadgeUrls             BadgeUrls `json:"badgeUrls"`
+	ClanLevel             int       `json:"clanLevel"`
+	ClanBuilderBasePoints int       `json:"clanBuilderBasePoints"`
+	Location              Location  `json:"location"`
+	Members               int       `json:"members"`
+	Name                  string    `json:"name"`
+	PreviousRank          int       `json:"previousRank"`
+	Rank                  int       `json:"rank"`
+	Tag                   string    `json:"tag"`
+}
+
+// String returns a string representation of a clan builder base ranking
+func (l ClanBuilderBaseRanking) String() string {
+	b, _ := json.Marshal(l)
+	return string(b)
+}
+
+// ClanCapitalRanking is the clan capital ranking for a specific location
+type ClanCapitalRanking struct {
+	BadgeUrls         BadgeUrls `json:"badgeUrls"`
+	ClanLevel         int       `json:"clanLevel"`
+	ClanCapitalPoints int       `json:"clanCapitalPoints"`
+	Location          Location  `json:"location"`
+	Members           int       `json:"members"`
+	Name              string    `json:"name"`
+	PreviousRank      int       `json:"previousRank"`
+	Rank              int       `json:"rank"`
+	Tag               string    `json:"tag"`
+}
+
+// String returns a string representation of a clan capital ranking
+func (l ClanCapitalRanking) String() string {
+	b, _ := json.Marshal(l)
+	return string(b)
+}
+
 // ClanReference provides a reference to a given clan
 type ClanReference struct {
 	BadgeUrls BadgeUrls `json:"badgeUrls"`
@@ -108,23 +145,46 @@ func (r ClanReference) String() string {
 
 // GetClan retrieves information about a clan with the given tag
 func GetClan(tag string) (*Clan, error) {
+	return defaultClient.GetClan(tag)
+}
+
+// GetClanContext retrieves information about a clan with the given tag
+func GetClanContext(ctx context.Context, tag string) (*Clan, error) {
+	return defaultClient.GetClanContext(ctx, tag)
+}
+
+// GetClan retrieves information about a clan with the given tag
+//
+// GetClan uses context.Background internally; to specify the context, use
+// GetClanContext.
+func (c *Client) GetClan(tag string) (*Clan, error) {
+	return c.GetClanContext(context.Background(), tag)
+}
+
+// GetClanContext retrieves information about a clan with the given tag
+func (c *Client) GetClanContext(ctx context.Context, tag string) (*Clan, error) {
+	ctx, span := c.startSpan(ctx, "GetClan", "/clans/{clanTag}", attribute.String("coc.tag", tag))
+	defer span.End()
+
 	// Build the URL
 	var sb strings.Builder
 	sb.Grow(100)
-	sb.WriteString(config.Data.BaseURL)
+	sb.WriteString(c.baseURL)
 	sb.WriteString("/clans/")
 	sb.WriteString(fmtTag(tag))
 	url := sb.String()
-	log.Trace(url)
 
 	// Get the clan
-	body, err := get(url, nil)
+	body, err := c.get(ctx, url, nil)
 	if err != nil {
+		if errors.Is(err, rest.ErrNotFound) {
+			return nil, ErrClanNotFound
+		}
 		return nil, err
 	}
 	var clan Clan
 	if err := json.Unmarshal(body, &clan); err != nil {
-		log.Debug("failed to parse the json response")
+		c.log.Debug("failed to parse the json response", "error", err)
 		return nil, err
 	}
 
@@ -132,115 +192,446 @@ func GetClan(tag string) (*Clan, error) {
 	return &clan, nil
 }
 
-// GetClans returns information about all clans that match the query parameters
-func GetClans(name string, qparms rest.QParms) ([]Clan, error) {
-	url := config.Data.BaseURL + "/clans"
-	body, err := get(url, qparms)
+// GetClans returns information about the clans whose name contains the given name
+func GetClans(name string, opts ...RequestOption) ([]Clan, error) {
+	return defaultClient.GetClans(name, opts...)
+}
+
+// GetClansContext returns information about the clans whose name contains the given name
+func GetClansContext(ctx context.Context, name string, opts ...RequestOption) ([]Clan, error) {
+	return defaultClient.GetClansContext(ctx, name, opts...)
+}
+
+// GetClansPage returns a single page of clans, along with the cursors of the
+// pages before and after it
+func GetClansPage(ctx context.Context, name string, opts ...RequestOption) (*Page[Clan], error) {
+	return defaultClient.GetClansPage(ctx, name, opts...)
+}
+
+// IterateClans returns an iterator over the clans, fetching pages as they are
+// needed. At most maxItems items are returned; if maxItems is zero, every item is
+// returned.
+func IterateClans(ctx context.Context, name string, maxItems int, opts ...RequestOption) *Iterator[Clan] {
+	return defaultClient.IterateClans(ctx, name, maxItems, opts...)
+}
+
+// GetClans returns information about the clans whose name contains the given name
+//
+// GetClans uses context.Background internally; to specify the context, use
+// GetClansContext.
+func (c *Client) GetClans(name string, opts ...RequestOption) ([]Clan, error) {
+	return c.GetClansContext(context.Background(), name, opts...)
+}
+
+// GetClansContext returns information about the clans whose name contains the given name
+func (c *Client) GetClansContext(ctx context.Context, name string, opts ...RequestOption) ([]Clan, error) {
+	page, err := c.GetClansPage(ctx, name, opts...)
 	if err != nil {
 		return nil, err
 	}
+	return page.Items, nil
+}
 
-	// Parse into an array of clans
-	type respType struct {
-		Clans []Clan `json:"items"`
-	}
-	var resp respType
-	err = json.Unmarshal(body, &resp)
+// GetClansPage returns a single page of clans, along with the cursors of the
+// pages before and after it
+func (c *Client) GetClansPage(ctx context.Context, name string, opts ...RequestOption) (*Page[Clan], error) {
+	return c.SearchClans(ctx, ClanSearch{Name: name}, opts...)
+}
+
+// IterateClans returns an iterator over the clans, fetching pages as they are
+// needed. At most maxItems items are returned; if maxItems is zero, every item is
+// returned.
+func (c *Client) IterateClans(ctx context.Context, name string, maxItems int, opts ...RequestOption) *Iterator[Clan] {
+	return newIterator(ctx, maxItems, opts, func(ctx context.Context, opts ...RequestOption) (*Page[Clan], error) {
+		return c.GetClansPage(ctx, name, opts...)
+	})
+}
+
+// GetClanMembers gets information about members of a given clan
+func GetClanMembers(clanTag string, opts ...RequestOption) ([]ClanMember, error) {
+	return defaultClient.GetClanMembers(clanTag, opts...)
+}
+
+// GetClanMembersContext gets information about members of a given clan
+func GetClanMembersContext(ctx context.Context, clanTag string, opts ...RequestOption) ([]ClanMember, error) {
+	return defaultClient.GetClanMembersContext(ctx, clanTag, opts...)
+}
+
+// GetClanMembersPage returns a single page of clan members, along with the cursors
+// of the pages before and after it
+func GetClanMembersPage(ctx context.Context, clanTag string, opts ...RequestOption) (*Page[ClanMember], error) {
+	return defaultClient.GetClanMembersPage(ctx, clanTag, opts...)
+}
+
+// IterateClanMembers returns an iterator over the clan members, fetching pages as
+// they are needed. At most maxItems items are returned; if maxItems is zero, every
+// item is returned.
+func IterateClanMembers(ctx context.Context, clanTag string, maxItems int, opts ...RequestOption) *Iterator[ClanMember] {
+	return defaultClient.IterateClanMembers(ctx, clanTag, maxItems, opts...)
+}
+
+// GetClanMembers gets information about members of a given clan
+//
+// GetClanMembers uses context.Background internally; to specify the context, use
+// GetClanMembersContext.
+func (c *Client) GetClanMembers(clanTag string, opts ...RequestOption) ([]ClanMember, error) {
+	return c.GetClanMembersContext(context.Background(), clanTag, opts...)
+}
+
+// GetClanMembersContext gets information about members of a given clan
+func (c *Client) GetClanMembersContext(ctx context.Context, clanTag string, opts ...RequestOption) ([]ClanMember, error) {
+	page, err := c.GetClanMembersPage(ctx, clanTag, opts...)
 	if err != nil {
-		log.Debug("failed to parse the json response")
 		return nil, err
 	}
-
-	return resp.Clans, nil
+	return page.Items, nil
 }
 
-// GetClanMembers gets information about members of a given clan
-func GetClanMembers(clanTag string, qparms rest.QParms) ([]ClanMember, error) {
+// GetClanMembersPage returns a single page of clan members, along with the cursors
+// of the pages before and after it
+func (c *Client) GetClanMembersPage(ctx context.Context, clanTag string, opts ...RequestOption) (*Page[ClanMember], error) {
+	ctx, span := c.startSpan(ctx, "GetClanMembers", "/clans/{clanTag}/members", attribute.String("coc.tag", clanTag))
+	defer span.End()
+
 	var sb strings.Builder
 	sb.Grow(100)
-	sb.WriteString(config.Data.BaseURL)
+	sb.WriteString(c.baseURL)
 	sb.WriteString("/clans/")
 	sb.WriteString(fmtTag(clanTag))
 	sb.WriteString("/members")
 
-	body, err := get(sb.String(), qparms)
+	body, err := c.get(ctx, sb.String(), requestParms(opts))
 	if err != nil {
 		return nil, err
 	}
-	fmt.Println(string(body))
 
-	// Parse into an array of clans
-	type respType struct {
-		ClanMembers []ClanMember `json:"items"`
+	// Parse into a page of clan members
+	var page Page[ClanMember]
+	if err := json.Unmarshal(body, &page); err != nil {
+		c.log.Debug("failed to parse the json response", "error", err)
+		return nil, err
 	}
-	var resp respType
-	err = json.Unmarshal(body, &resp)
+
+	return &page, nil
+}
+
+// IterateClanMembers returns an iterator over the clan members, fetching pages as
+// they are needed. At most maxItems items are returned; if maxItems is zero, every
+// item is returned.
+func (c *Client) IterateClanMembers(ctx context.Context, clanTag string, maxItems int, opts ...RequestOption) *Iterator[ClanMember] {
+	return newIterator(ctx, maxItems, opts, func(ctx context.Context, opts ...RequestOption) (*Page[ClanMember], error) {
+		return c.GetClanMembersPage(ctx, clanTag, opts...)
+	})
+}
+
+// GetClanRankings gets clan rankings for a specific location
+func GetClanRankings(locationID string, opts ...RequestOption) ([]ClanRanking, error) {
+	return defaultClient.GetClanRankings(locationID, opts...)
+}
+
+// GetClanRankingsContext gets clan rankings for a specific location
+func GetClanRankingsContext(ctx context.Context, locationID string, opts ...RequestOption) ([]ClanRanking, error) {
+	return defaultClient.GetClanRankingsContext(ctx, locationID, opts...)
+}
+
+// GetClanRankingsPage returns a single page of clan rankings, along with the cursors
+// of the pages before and after it
+func GetClanRankingsPage(ctx context.Context, locationID string, opts ...RequestOption) (*Page[ClanRanking], error) {
+	return defaultClient.GetClanRankingsPage(ctx, locationID, opts...)
+}
+
+// IterateClanRankings returns an iterator over the clan rankings, fetching pages as
+// they are needed. At most maxItems items are returned; if maxItems is zero, every
+// item is returned.
+func IterateClanRankings(ctx context.Context, locationID string, maxItems int, opts ...RequestOption) *Iterator[ClanRanking] {
+	return defaultClient.IterateClanRankings(ctx, locationID, maxItems, opts...)
+}
+
+// GetClanRankings gets clan rankings for a specific location
+//
+// GetClanRankings uses context.Background internally; to specify the context, use
+// GetClanRankingsContext.
+func (c *Client) GetClanRankings(locationID string, opts ...RequestOption) ([]ClanRanking, error) {
+	return c.GetClanRankingsContext(context.Background(), locationID, opts...)
+}
+
+// GetClanRankingsContext gets clan rankings for a specific location
+func (c *Client) GetClanRankingsContext(ctx context.Context, locationID string, opts ...RequestOption) ([]ClanRanking, error) {
+	page, err := c.GetClanRankingsPage(ctx, locationID, opts...)
 	if err != nil {
-		log.Debug("failed to parse the json response")
 		return nil, err
 	}
-
-	return resp.ClanMembers, nil
+	return page.Items, nil
 }
 
-// GetClan Rankings gets clan rankings for a specific location
-func GetClanRankings(locationID string, qparms rest.QParms) ([]ClanRanking, error) {
+// GetClanRankingsPage returns a single page of clan rankings, along with the cursors
+// of the pages before and after it
+func (c *Client) GetClanRankingsPage(ctx context.Context, locationID string, opts ...RequestOption) (*Page[ClanRanking], error) {
+	ctx, span := c.startSpan(ctx, "GetClanRankings", "/locations/{locationId}/rankings/clans", attribute.String("coc.location_id", locationID))
+	defer span.End()
+
 	var sb strings.Builder
 	sb.Grow(100)
-	sb.WriteString(config.Data.BaseURL)
+	sb.WriteString(c.baseURL)
 	sb.WriteString("/locations/")
-	sb.WriteString(fmtTag(locationID))
+	sb.WriteString(locationID)
 	sb.WriteString("/rankings/clans")
 	url := sb.String()
-	log.Trace(url)
 
-	body, err := get(url, nil)
+	body, err := c.get(ctx, url, requestParms(opts))
 	if err != nil {
 		return nil, err
 	}
 
-	// Parse into an array of clan rankings
-	type respType struct {
-		Rankings []ClanRanking `json:"items"`
+	// Parse into a page of clan rankings
+	var page Page[ClanRanking]
+	if err := json.Unmarshal(body, &page); err != nil {
+		c.log.Debug("failed to parse the json response", "error", err)
+		return nil, err
+	}
+
+	return &page, nil
+}
+
+// IterateClanRankings returns an iterator over the clan rankings, fetching pages as
+// they are needed. At most maxItems items are returned; if maxItems is zero, every
+// item is returned.
+func (c *Client) IterateClanRankings(ctx context.Context, locationID string, maxItems int, opts ...RequestOption) *Iterator[ClanRanking] {
+	return newIterator(ctx, maxItems, opts, func(ctx context.Context, opts ...RequestOption) (*Page[ClanRanking], error) {
+		return c.GetClanRankingsPage(ctx, locationID, opts...)
+	})
+}
+
+// GetClanVersusRankings gets clan versus rankings for a specific location
+func GetClanVersusRankings(locationID string, opts ...RequestOption) ([]ClanVersusRanking, error) {
+	return defaultClient.GetClanVersusRankings(locationID, opts...)
+}
+
+// GetClanVersusRankingsContext gets clan versus rankings for a specific location
+func GetClanVersusRankingsContext(ctx context.Context, locationID string, opts ...RequestOption) ([]ClanVersusRanking, error) {
+	return defaultClient.GetClanVersusRankingsContext(ctx, locationID, opts...)
+}
+
+// GetClanVersusRankingsPage returns a single page of clan versus rankings, along
+// with the cursors of the pages before and after it
+func GetClanVersusRankingsPage(ctx context.Context, locationID string, opts ...RequestOption) (*Page[ClanVersusRanking], error) {
+	return defaultClient.GetClanVersusRankingsPage(ctx, locationID, opts...)
+}
+
+// IterateClanVersusRankings returns an iterator over the clan versus rankings,
+// fetching pages as they are needed. At most maxItems items are returned; if
+// maxItems is zero, every item is returned.
+func IterateClanVersusRankings(ctx context.Context, locationID string, maxItems int, opts ...RequestOption) *Iterator[ClanVersusRanking] {
+	return defaultClient.IterateClanVersusRankings(ctx, locationID, maxItems, opts...)
+}
+
+// GetClanVersusRankings gets clan versus rankings for a specific location
+//
+// GetClanVersusRankings uses context.Background internally; to specify the context, use
+// GetClanVersusRankingsContext.
+func (c *Client) GetClanVersusRankings(locationID string, opts ...RequestOption) ([]ClanVersusRanking, error) {
+	return c.GetClanVersusRankingsContext(context.Background(), locationID, opts...)
+}
+
+// GetClanVersusRankingsContext gets clan versus rankings for a specific location
+func (c *Client) GetClanVersusRankingsContext(ctx context.Context, locationID string, opts ...RequestOption) ([]ClanVersusRanking, error) {
+	page, err := c.GetClanVersusRankingsPage(ctx, locationID, opts...)
+	if err != nil {
+		return nil, err
 	}
-	var resp respType
-	err = json.Unmarshal(body, &resp)
+	return page.Items, nil
+}
+
+// GetClanVersusRankingsPage returns a single page of clan versus rankings, along
+// with the cursors of the pages before and after it
+func (c *Client) GetClanVersusRankingsPage(ctx context.Context, locationID string, opts ...RequestOption) (*Page[ClanVersusRanking], error) {
+	ctx, span := c.startSpan(ctx, "GetClanVersusRankings", "/locations/{locationId}/rankings/clan-versus", attribute.String("coc.location_id", locationID))
+	defer span.End()
+
+	var sb strings.Builder
+	sb.Grow(100)
+	sb.WriteString(c.baseURL)
+	sb.WriteString("/locations/")
+	sb.WriteString(locationID)
+	sb.WriteString("/rankings/clan-versus")
+	url := sb.String()
+
+	body, err := c.get(ctx, url, requestParms(opts))
 	if err != nil {
-		log.Debug("failed to parse the json response")
 		return nil, err
 	}
 
-	// Return the clan
-	return resp.Rankings, nil
+	// Parse into a page of clan versus rankings
+	var page Page[ClanVersusRanking]
+	if err := json.Unmarshal(body, &page); err != nil {
+		c.log.Debug("failed to parse the json response", "error", err)
+		return nil, err
+	}
+
+	return &page, nil
 }
 
-// GetClanRankings gets clan versus rankings for a specific location
-func GetClanVersusRankings(locationID string, qparms rest.QParms) ([]ClanVersusRanking, error) {
+// IterateClanVersusRankings returns an iterator over the clan versus rankings,
+// fetching pages as they are needed. At most maxItems items are returned; if
+// maxItems is zero, every item is returned.
+func (c *Client) IterateClanVersusRankings(ctx context.Context, locationID string, maxItems int, opts ...RequestOption) *Iterator[ClanVersusRanking] {
+	return newIterator(ctx, maxItems, opts, func(ctx context.Context, opts ...RequestOption) (*Page[ClanVersusRanking], error) {
+		return c.GetClanVersusRankingsPage(ctx, locationID, opts...)
+	})
+}
+
+// GetClanBuilderBaseRankings gets clan builder base rankings for a specific location
+func GetClanBuilderBaseRankings(locationID string, opts ...RequestOption) ([]ClanBuilderBaseRanking, error) {
+	return defaultClient.GetClanBuilderBaseRankings(locationID, opts...)
+}
+
+// GetClanBuilderBaseRankingsContext gets clan builder base rankings for a specific location
+func GetClanBuilderBaseRankingsContext(ctx context.Context, locationID string, opts ...RequestOption) ([]ClanBuilderBaseRanking, error) {
+	return defaultClient.GetClanBuilderBaseRankingsContext(ctx, locationID, opts...)
+}
+
+// GetClanBuilderBaseRankingsPage returns a single page of clan builder base
+// rankings, along with the cursors of the pages before and after it
+func GetClanBuilderBaseRankingsPage(ctx context.Context, locationID string, opts ...RequestOption) (*Page[ClanBuilderBaseRanking], error) {
+	return defaultClient.GetClanBuilderBaseRankingsPage(ctx, locationID, opts...)
+}
+
+// IterateClanBuilderBaseRankings returns an iterator over the clan builder base
+// rankings, fetching pages as they are needed. At most maxItems items are returned;
+// if maxItems is zero, every item is returned.
+func IterateClanBuilderBaseRankings(ctx context.Context, locationID string, maxItems int, opts ...RequestOption) *Iterator[ClanBuilderBaseRanking] {
+	return defaultClient.IterateClanBuilderBaseRankings(ctx, locationID, maxItems, opts...)
+}
+
+// GetClanBuilderBaseRankings gets clan builder base rankings for a specific location
+//
+// GetClanBuilderBaseRankings uses context.Background internally; to specify the
+// context, use GetClanBuilderBaseRankingsContext.
+func (c *Client) GetClanBuilderBaseRankings(locationID string, opts ...RequestOption) ([]ClanBuilderBaseRanking, error) {
+	return c.GetClanBuilderBaseRankingsContext(context.Background(), locationID, opts...)
+}
+
+// GetClanBuilderBaseRankingsContext gets clan builder base rankings for a specific location
+func (c *Client) GetClanBuilderBaseRankingsContext(ctx context.Context, locationID string, opts ...RequestOption) ([]ClanBuilderBaseRanking, error) {
+	page, err := c.GetClanBuilderBaseRankingsPage(ctx, locationID, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+// GetClanBuilderBaseRankingsPage returns a single page of clan builder base
+// rankings, along with the cursors of the pages before and after it
+func (c *Client) GetClanBuilderBaseRankingsPage(ctx context.Context, locationID string, opts ...RequestOption) (*Page[ClanBuilderBaseRanking], error) {
+	ctx, span := c.startSpan(ctx, "GetClanBuilderBaseRankings", "/locations/{locationId}/rankings/clans-builder-base", attribute.String("coc.location_id", locationID))
+	defer span.End()
+
 	var sb strings.Builder
 	sb.Grow(100)
-	sb.WriteString(config.Data.BaseURL)
+	sb.WriteString(c.baseURL)
 	sb.WriteString("/locations/")
-	sb.WriteString(fmtTag(locationID))
-	sb.WriteString("/rankings/clan-versus")
+	sb.WriteString(locationID)
+	sb.WriteString("/rankings/clans-builder-base")
 	url := sb.String()
-	log.Trace(url)
 
-	body, err := get(url, nil)
+	body, err := c.get(ctx, url, requestParms(opts))
 	if err != nil {
 		return nil, err
 	}
 
-	// Parse into an array of clan rankings
-	type respType struct {
-		Rankings []ClanVersusRanking `json:"items"`
+	// Parse into a page of clan builder base rankings
+	var page Page[ClanBuilderBaseRanking]
+	if err := json.Unmarshal(body, &page); err != nil {
+		c.log.Debug("failed to parse the json response", "error", err)
+		return nil, err
+	}
+
+	return &page, nil
+}
+
+// IterateClanBuilderBaseRankings returns an iterator over the clan builder base
+// rankings, fetching pages as they are needed. At most maxItems items are returned;
+// if maxItems is zero, every item is returned.
+func (c *Client) IterateClanBuilderBaseRankings(ctx context.Context, locationID string, maxItems int, opts ...RequestOption) *Iterator[ClanBuilderBaseRanking] {
+	return newIterator(ctx, maxItems, opts, func(ctx context.Context, opts ...RequestOption) (*Page[ClanBuilderBaseRanking], error) {
+		return c.GetClanBuilderBaseRankingsPage(ctx, locationID, opts...)
+	})
+}
+
+// GetClanCapitalRankings gets clan capital rankings for a specific location
+func GetClanCapitalRankings(locationID string, opts ...RequestOption) ([]ClanCapitalRanking, error) {
+	return defaultClient.GetClanCapitalRankings(locationID, opts...)
+}
+
+// GetClanCapitalRankingsContext gets clan capital rankings for a specific location
+func GetClanCapitalRankingsContext(ctx context.Context, locationID string, opts ...RequestOption) ([]ClanCapitalRanking, error) {
+	return defaultClient.GetClanCapitalRankingsContext(ctx, locationID, opts...)
+}
+
+// GetClanCapitalRankingsPage returns a single page of clan capital rankings, along
+// with the cursors of the pages before and after it
+func GetClanCapitalRankingsPage(ctx context.Context, locationID string, opts ...RequestOption) (*Page[ClanCapitalRanking], error) {
+	return defaultClient.GetClanCapitalRankingsPage(ctx, locationID, opts...)
+}
+
+// IterateClanCapitalRankings returns an iterator over the clan capital rankings,
+// fetching pages as they are needed. At most maxItems items are returned; if
+// maxItems is zero, every item is returned.
+func IterateClanCapitalRankings(ctx context.Context, locationID string, maxItems int, opts ...RequestOption) *Iterator[ClanCapitalRanking] {
+	return defaultClient.IterateClanCapitalRankings(ctx, locationID, maxItems, opts...)
+}
+
+// GetClanCapitalRankings gets clan capital rankings for a specific location
+//
+// GetClanCapitalRankings uses context.Background internally; to specify the context,
+// use GetClanCapitalRankingsContext.
+func (c *Client) GetClanCapitalRankings(locationID string, opts ...RequestOption) ([]ClanCapitalRanking, error) {
+	return c.GetClanCapitalRankingsContext(context.Background(), locationID, opts...)
+}
+
+// GetClanCapitalRankingsContext gets clan capital rankings for a specific location
+func (c *Client) GetClanCapitalRankingsContext(ctx context.Context, locationID string, opts ...RequestOption) ([]ClanCapitalRanking, error) {
+	page, err := c.GetClanCapitalRankingsPage(ctx, locationID, opts...)
+	if err != nil {
+		return nil, err
 	}
-	var resp respType
-	err = json.Unmarshal(body, &resp)
+	return page.Items, nil
+}
+
+// GetClanCapitalRankingsPage returns a single page of clan capital rankings, along
+// with the cursors of the pages before and after it
+func (c *Client) GetClanCapitalRankingsPage(ctx context.Context, locationID string, opts ...RequestOption) (*Page[ClanCapitalRanking], error) {
+	ctx, span := c.startSpan(ctx, "GetClanCapitalRankings", "/locations/{locationId}/rankings/capitals", attribute.String("coc.location_id", locationID))
+	defer span.End()
+
+	var sb strings.Builder
+	sb.Grow(100)
+	sb.WriteString(c.baseURL)
+	sb.WriteString("/locations/")
+	sb.WriteString(locationID)
+	sb.WriteString("/rankings/capitals")
+	url := sb.String()
+
+	body, err := c.get(ctx, url, requestParms(opts))
 	if err != nil {
-		log.Debug("failed to parse the json response")
 		return nil, err
 	}
 
-	// Return the clan
-	return resp.Rankings, nil
+	// Parse into a page of clan capital rankings
+	var page Page[ClanCapitalRanking]
+	if err := json.Unmarshal(body, &page); err != nil {
+		c.log.Debug("failed to parse the json response", "error", err)
+		return nil, err
+	}
+
+	return &page, nil
+}
+
+// IterateClanCapitalRankings returns an iterator over the clan capital rankings,
+// fetching pages as they are needed. At most maxItems items are returned; if
+// maxItems is zero, every item is returned.
+func (c *Client) IterateClanCapitalRankings(ctx context.Context, locationID string, maxItems int, opts ...RequestOption) *Iterator[ClanCapitalRanking] {
+	return newIterator(ctx, maxItems, opts, func(ctx context.Context, opts ...RequestOption) (*Page[ClanCapitalRanking], error) {
+		return c.GetClanCapitalRankingsPage(ctx, locationID, opts...)
+	})
 }
@@ -0,0 +1,222 @@
+package coc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/clashgolang/coc/pkg/rest"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// War frequencies that may be used to filter a clan search
+const (
+	WarFrequencyAlways              = "always"
+	WarFrequencyMoreThanOncePerWeek = "moreThanOncePerWeek"
+	WarFrequencyOncePerWeek         = "oncePerWeek"
+	WarFrequencyLessThanOncePerWeek = "lessThanOncePerWeek"
+	WarFrequencyNever               = "never"
+	WarFrequencyUnknown             = "unknown"
+)
+
+const (
+	minSearchNameLength = 3
+	minClanMembers      = 2
+	maxClanMembers      = 50
+	minSearchClanLevel  = 2
+)
+
+var (
+	ErrInvalidSearch = errors.New("invalid clan search")
+	ErrUnknownLabel  = errors.New("unknown clan label")
+)
+
+// ClanSearch is a search for clans. At least one of the fields must be set. Zero
+// values are not included in the search.
+type ClanSearch struct {
+	// Name searches for clans whose name contains the value; it must be at least
+	// three characters long
+	Name string
+	// WarFrequency is the war frequency of the clans, such as WarFrequencyAlways
+	WarFrequency string
+	// LocationID is the ID of the location of the clans
+	LocationID int
+	// MinMembers is the minimum number of members in the clans
+	MinMembers int
+	// MaxMembers is the maximum number of members in the clans
+	MaxMembers int
+	// MinClanPoints is the minimum number of clan points of the clans
+	MinClanPoints int
+	// MinClanLevel is the minimum level of the clans
+	MinClanLevel int
+	// LabelIDs are the IDs of labels the clans must have
+	LabelIDs []int
+	// Labels are the names of labels the clans must have. The names are resolved to
+	// IDs using the list of clan labels.
+	Labels []string
+}
+
+// String returns a string representation of a clan search
+func (s ClanSearch) String() string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// Validate checks that the search may be sent to Clash of Clans
+func (s ClanSearch) Validate() error {
+	switch {
+	case s.Name == "" && s.WarFrequency == "" && s.LocationID == 0 && s.MinMembers == 0 &&
+		s.MaxMembers == 0 && s.MinClanPoints == 0 && s.MinClanLevel == 0 &&
+		len(s.LabelIDs) == 0 && len(s.Labels) == 0:
+		return fmt.Errorf("%w: at least one filter must be set", ErrInvalidSearch)
+	case s.Name != "" && len([]rune(s.Name)) < minSearchNameLength:
+		return fmt.Errorf("%w: name must be at least %d characters", ErrInvalidSearch, minSearchNameLength)
+	case s.MinMembers != 0 && (s.MinMembers < minClanMembers || s.MinMembers > maxClanMembers):
+		return fmt.Errorf("%w: minimum members must be between %d and %d", ErrInvalidSearch, minClanMembers, maxClanMembers)
+	case s.MaxMembers != 0 && (s.MaxMembers < minClanMembers || s.MaxMembers > maxClanMembers):
+		return fmt.Errorf("%w: maximum members must be between %d and %d", ErrInvalidSearch, minClanMembers, maxClanMembers)
+	case s.MaxMembers != 0 && s.MinMembers > s.MaxMembers:
+		return fmt.Errorf("%w: minimum members is greater than maximum members", ErrInvalidSearch)
+	case s.MinClanPoints < 0:
+		return fmt.Errorf("%w: minimum clan points may not be negative", ErrInvalidSearch)
+	case s.MinClanLevel != 0 && s.MinClanLevel < minSearchClanLevel:
+		return fmt.Errorf("%w: minimum clan level must be at least %d", ErrInvalidSearch, minSearchClanLevel)
+	}
+	switch s.WarFrequency {
+	case "", WarFrequencyAlways, WarFrequencyMoreThanOncePerWeek, WarFrequencyOncePerWeek,
+		WarFrequencyLessThanOncePerWeek, WarFrequencyNever, WarFrequencyUnknown:
+	default:
+		return fmt.Errorf("%w: unknown war frequency %q", ErrInvalidSearch, s.WarFrequency)
+	}
+	return nil
+}
+
+// SearchClans returns a single page of the clans that match the search, along with
+// the cursors of the pages before and after it
+func SearchClans(ctx context.Context, search ClanSearch, opts ...RequestOption) (*Page[Clan], error) {
+	return defaultClient.SearchClans(ctx, search, opts...)
+}
+
+// IterateClanSearch returns an iterator over the clans that match the search,
+// fetching pages as they are needed. At most maxItems items are returned; if
+// maxItems is zero, every item is returned.
+func IterateClanSearch(ctx context.Context, search ClanSearch, maxItems int, opts ...RequestOption) *Iterator[Clan] {
+	return defaultClient.IterateClanSearch(ctx, search, maxItems, opts...)
+}
+
+// SearchClans returns a single page of the clans that match the search, along with
+// the cursors of the pages before and after it
+func (c *Client) SearchClans(ctx context.Context, search ClanSearch, opts ...RequestOption) (*Page[Clan], error) {
+	if err := search.Validate(); err != nil {
+		return nil, err
+	}
+	labelIDs, err := c.resolveLabels(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+	return c.searchClans(ctx, search, labelIDs, opts...)
+}
+
+// IterateClanSearch returns an iterator over the clans that match the search,
+// fetching pages as they are needed. At most maxItems items are returned; if
+// maxItems is zero, every item is returned.
+func (c *Client) IterateClanSearch(ctx context.Context, search ClanSearch, maxItems int, opts ...RequestOption) *Iterator[Clan] {
+	// Validate the search and look up the labels once, rather than for every page
+	if err := search.Validate(); err != nil {
+		return &Iterator[Clan]{err: err, done: true}
+	}
+	labelIDs, err := c.resolveLabels(ctx, search)
+	if err != nil {
+		return &Iterator[Clan]{err: err, done: true}
+	}
+	return newIterator(ctx, maxItems, opts, func(ctx context.Context, opts ...RequestOption) (*Page[Clan], error) {
+		return c.searchClans(ctx, search, labelIDs, opts...)
+	})
+}
+
+// searchClans returns a single page of the clans that match a search that has been
+// validated, using the IDs of its labels
+func (c *Client) searchClans(ctx context.Context, search ClanSearch, labelIDs []int, opts ...RequestOption) (*Page[Clan], error) {
+	ctx, span := c.startSpan(ctx, "SearchClans", "/clans", attribute.String("coc.name", search.Name))
+	defer span.End()
+
+	// Add the search filters to the query parameters
+	qparms := requestParms(opts)
+	if qparms == nil {
+		qparms = rest.QParms{}
+	}
+	if search.Name != "" {
+		qparms["name"] = search.Name
+	}
+	if search.WarFrequency != "" {
+		qparms["warFrequency"] = search.WarFrequency
+	}
+	if search.LocationID != 0 {
+		qparms["locationId"] = search.LocationID
+	}
+	if search.MinMembers != 0 {
+		qparms["minMembers"] = search.MinMembers
+	}
+	if search.MaxMembers != 0 {
+		qparms["maxMembers"] = search.MaxMembers
+	}
+	if search.MinClanPoints != 0 {
+		qparms["minClanPoints"] = search.MinClanPoints
+	}
+	if search.MinClanLevel != 0 {
+		qparms["minClanLevel"] = search.MinClanLevel
+	}
+	if len(labelIDs) > 0 {
+		ids := make([]string, len(labelIDs))
+		for i, id := range labelIDs {
+			ids[i] = strconv.Itoa(id)
+		}
+		qparms["labelIds"] = strings.Join(ids, ",")
+	}
+
+	url := c.baseURL + "/clans"
+	body, err := c.get(ctx, url, qparms)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse into a page of clans
+	var page Page[Clan]
+	if err := json.Unmarshal(body, &page); err != nil {
+		c.log.Debug("failed to parse the json response", "error", err)
+		return nil, err
+	}
+
+	return &page, nil
+}
+
+// resolveLabels returns the IDs of the labels in the search, looking up the IDs of
+// any labels given by name.
+func (c *Client) resolveLabels(ctx context.Context, search ClanSearch) ([]int, error) {
+	if len(search.Labels) == 0 {
+		return search.LabelIDs, nil
+	}
+
+	labels, err := c.GetClanLabelsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := append([]int(nil), search.LabelIDs...)
+	for _, name := range search.Labels {
+		found := false
+		for _, label := range labels {
+			if strings.EqualFold(label.Name, name) {
+				ids = append(ids, label.ID)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownLabel, name)
+		}
+	}
+	return ids, nil
+}
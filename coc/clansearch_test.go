@@ -0,0 +1,68 @@
+package coc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestIterateClanSearchResolvesLabelsOnce(t *testing.T) {
+	var labelRequests, clanRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/labels/clans"):
+			labelRequests.Add(1)
+			w.Write([]byte(`{"items":[{"id":56000000,"name":"Clan Wars"}],"paging":{"cursors":{}}}`))
+		case r.URL.Path == "/clans":
+			if got := r.URL.Query().Get("labelIds"); got != "56000000" {
+				t.Errorf("got labelIds %q, want 56000000", got)
+			}
+			page := clanRequests.Add(1)
+			after := ""
+			if page < 3 {
+				after = fmt.Sprintf(`"after":"page%d"`, page+1)
+			}
+			fmt.Fprintf(w, `{"items":[{"tag":"#C%d"}],"paging":{"cursors":{%s}}}`, page, after)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("token", WithBaseURL(server.URL))
+	it := client.IterateClanSearch(context.Background(), ClanSearch{Labels: []string{"clan wars"}}, 0)
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 3 {
+		t.Errorf("got %d clans, want 3", count)
+	}
+	if got := clanRequests.Load(); got != 3 {
+		t.Errorf("got %d clan search requests, want 3", got)
+	}
+	if got := labelRequests.Load(); got != 1 {
+		t.Errorf("got %d label requests, want 1", got)
+	}
+}
+
+func TestIterateClanSearchInvalid(t *testing.T) {
+	client := NewClient("token", WithBaseURL("http://127.0.0.1:0"))
+
+	it := client.IterateClanSearch(context.Background(), ClanSearch{Name: "ab"}, 0)
+	if it.Next() {
+		t.Fatal("got an item, want none for an invalid search")
+	}
+	if err := it.Err(); !errors.Is(err, ErrInvalidSearch) {
+		t.Errorf("got %v, want ErrInvalidSearch", err)
+	}
+}
@@ -1,12 +1,11 @@
 package coc
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 
-	"github.com/clashgolang/coc/pkg/config"
-	"github.com/clashgolang/coc/pkg/rest"
-	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // ClanWar is a given war in a clan's war log.
@@ -79,74 +78,128 @@ func (cwa ClanWarAttack) String() string {
 }
 
 // GetClanWars returns a list of wars a clan has particiapted in
-func GetClanWars(clanTag string, qparms rest.QParms) ([]ClanWar, error) {
+func GetClanWars(clanTag string, opts ...RequestOption) ([]ClanWar, error) {
+	return defaultClient.GetClanWars(clanTag, opts...)
+}
+
+// GetClanWarsContext returns a list of wars a clan has particiapted in
+func GetClanWarsContext(ctx context.Context, clanTag string, opts ...RequestOption) ([]ClanWar, error) {
+	return defaultClient.GetClanWarsContext(ctx, clanTag, opts...)
+}
+
+// GetClanWarsPage returns a single page of clan wars, along with the cursors of the
+// pages before and after it
+func GetClanWarsPage(ctx context.Context, clanTag string, opts ...RequestOption) (*Page[ClanWar], error) {
+	return defaultClient.GetClanWarsPage(ctx, clanTag, opts...)
+}
+
+// IterateClanWars returns an iterator over the clan wars, fetching pages as they are
+// needed. At most maxItems items are returned; if maxItems is zero, every item is
+// returned.
+func IterateClanWars(ctx context.Context, clanTag string, maxItems int, opts ...RequestOption) *Iterator[ClanWar] {
+	return defaultClient.IterateClanWars(ctx, clanTag, maxItems, opts...)
+}
+
+// GetClanWars returns a list of wars a clan has particiapted in
+//
+// GetClanWars uses context.Background internally; to specify the context, use
+// GetClanWarsContext.
+func (c *Client) GetClanWars(clanTag string, opts ...RequestOption) ([]ClanWar, error) {
+	return c.GetClanWarsContext(context.Background(), clanTag, opts...)
+}
+
+// GetClanWarsContext returns a list of wars a clan has particiapted in
+func (c *Client) GetClanWarsContext(ctx context.Context, clanTag string, opts ...RequestOption) ([]ClanWar, error) {
+	page, err := c.GetClanWarsPage(ctx, clanTag, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+// GetClanWarsPage returns a single page of clan wars, along with the cursors of the
+// pages before and after it
+func (c *Client) GetClanWarsPage(ctx context.Context, clanTag string, opts ...RequestOption) (*Page[ClanWar], error) {
+	ctx, span := c.startSpan(ctx, "GetClanWars", "/clans/{clanTag}/warlog", attribute.String("coc.tag", clanTag))
+	defer span.End()
+
 	var sb strings.Builder
 	sb.Grow(100)
-	sb.WriteString(config.Data.BaseURL)
+	sb.WriteString(c.baseURL)
 	sb.WriteString("/clans/")
 	sb.WriteString(fmtTag(clanTag))
 	sb.WriteString("/warlog")
 	url := sb.String()
-	log.Trace(url)
 
-	body, err := get(url, qparms)
+	body, err := c.get(ctx, url, requestParms(opts))
 	if err != nil {
 		return nil, err
 	}
 
-	// Parse into an array of clans
-	type respType struct {
-		WarLog []ClanWar `json:"items"`
-	}
-	var resp respType
-	err = json.Unmarshal(body, &resp)
-	if err != nil {
-		log.Debug("failed to parse the json response")
+	// Parse into a page of clan wars
+	var page Page[ClanWar]
+	if err := json.Unmarshal(body, &page); err != nil {
+		c.log.Debug("failed to parse the json response", "error", err)
 		return nil, err
 	}
 
-	// Find any "empty" wars and remove it from the list
-	for i := range resp.WarLog {
-		if i >= len(resp.WarLog) {
-			break
-		}
-		war := resp.WarLog[i]
-		if war.Opponent.Name == "" {
-			if i == len(resp.WarLog) {
-				resp.WarLog = resp.WarLog[:i]
-			} else {
-				resp.WarLog = append(resp.WarLog[:i], resp.WarLog[i+1:]...)
-			}
-		}
-	}
-
 	// Remove wars without an opponent clan's name
 	i := 0
-	warLog := make([]ClanWar, len(resp.WarLog))
-	for _, war := range resp.WarLog {
+	warLog := make([]ClanWar, len(page.Items))
+	for _, war := range page.Items {
 		if war.Opponent.Name != "" {
 			warLog[i] = war
 			i++
 		}
 	}
+	page.Items = warLog[:i]
 
 	// Return the trimmed war log
-	return warLog[:i], nil
+	return &page, nil
+}
+
+// IterateClanWars returns an iterator over the clan wars, fetching pages as they are
+// needed. At most maxItems items are returned; if maxItems is zero, every item is
+// returned.
+func (c *Client) IterateClanWars(ctx context.Context, clanTag string, maxItems int, opts ...RequestOption) *Iterator[ClanWar] {
+	return newIterator(ctx, maxItems, opts, func(ctx context.Context, opts ...RequestOption) (*Page[ClanWar], error) {
+		return c.GetClanWarsPage(ctx, clanTag, opts...)
+	})
 }
 
 // GetCurrentWar returns information about the current war a clan is participating in
 func GetCurrentWar(clanTag string) (*ClanWar, error) {
+	return defaultClient.GetCurrentWar(clanTag)
+}
+
+// GetCurrentWarContext returns information about the current war a clan is participating in
+func GetCurrentWarContext(ctx context.Context, clanTag string) (*ClanWar, error) {
+	return defaultClient.GetCurrentWarContext(ctx, clanTag)
+}
+
+// GetCurrentWar returns information about the current war a clan is participating in
+//
+// GetCurrentWar uses context.Background internally; to specify the context, use
+// GetCurrentWarContext.
+func (c *Client) GetCurrentWar(clanTag string) (*ClanWar, error) {
+	return c.GetCurrentWarContext(context.Background(), clanTag)
+}
+
+// GetCurrentWarContext returns information about the current war a clan is participating in
+func (c *Client) GetCurrentWarContext(ctx context.Context, clanTag string) (*ClanWar, error) {
+	ctx, span := c.startSpan(ctx, "GetCurrentWar", "/clans/{clanTag}/currentwar", attribute.String("coc.tag", clanTag))
+	defer span.End()
+
 	var sb strings.Builder
 	sb.Grow(100)
-	sb.WriteString(config.Data.BaseURL)
+	sb.WriteString(c.baseURL)
 	sb.WriteString("/clans/")
 	sb.WriteString(fmtTag(clanTag))
 	sb.WriteString("/currentwar")
 	url := sb.String()
-	log.Trace(url)
 
 	// Send the request and get the response
-	body, err := get(url, nil)
+	body, err := c.get(ctx, url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -154,7 +207,7 @@ func GetCurrentWar(clanTag string) (*ClanWar, error) {
 	// Parse into a war
 	var war ClanWar
 	if err := json.Unmarshal(body, &war); err != nil {
-		log.Debug("failed to parse the json response")
+		c.log.Debug("failed to parse the json response", "error", err)
 		return nil, err
 	}
 
@@ -0,0 +1,277 @@
+package coc
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/clashgolang/coc/pkg/config"
+	"github.com/clashgolang/coc/pkg/rest"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	// defaultClient is the client used by the package-level functions
+	defaultClient = NewClient("")
+)
+
+// Client is a client that sends requests to the Clash of Clans API. Each client
+// has its own tokens, base URL, HTTP client and logger, so multiple clients may be
+// used within the same process. A client is safe for concurrent use, and should be
+// reused so that connections to the server are pooled.
+type Client struct {
+	keys            *keyPool
+	baseURL         string
+	httpClient      *http.Client
+	transport       http.RoundTripper
+	transportConfig rest.TransportConfig
+	limiter         *rest.RateLimiter
+	retry           *rest.RetryPolicy
+	cache           rest.Cache
+	interceptors    []rest.Interceptor
+	metrics         rest.Metrics
+	tracerProvider  trace.TracerProvider
+	tracer          trace.Tracer
+	breaker         *rest.CircuitBreaker
+	concurrency     int
+	coalescer       *rest.Coalescer
+	log             rest.Logger
+
+	mu          sync.RWMutex
+	restClients map[string]rest.Client
+}
+
+// ClientOption configures an optional setting on a client
+type ClientOption func(*Client)
+
+// WithBaseURL sets the base URL of the Clash of Clans API
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to send requests to Clash of Clans
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTransport sets the transport used to send requests to Clash of Clans
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.transport = transport
+	}
+}
+
+// WithTransportConfig sets the connection pool settings, such as the number of idle
+// connections and the keep-alive interval, of the client's transport. It has no
+// effect if an HTTP client or transport is also provided.
+func WithTransportConfig(config rest.TransportConfig) ClientOption {
+	return func(c *Client) {
+		c.transportConfig = config
+	}
+}
+
+// WithRateLimiter sets the rate limiter shared by all requests sent by the client
+func WithRateLimiter(limiter *rest.RateLimiter) ClientOption {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
+
+// WithRetryPolicy sets the policy used to retry requests that fail with a transient error
+func WithRetryPolicy(policy rest.RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retry = &policy
+	}
+}
+
+// WithCache sets the cache used to store responses from Clash of Clans
+func WithCache(cache rest.Cache) ClientOption {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// WithInterceptors adds interceptors that are called before each request is sent to
+// Clash of Clans and after each response is received
+func WithInterceptors(interceptors ...rest.Interceptor) ClientOption {
+	return func(c *Client) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+// WithMetrics sets where measurements of the requests sent to Clash of Clans are
+// recorded, such as a Prometheus collector from the metrics package
+func WithMetrics(metrics rest.Metrics) ClientOption {
+	return func(c *Client) {
+		c.metrics = metrics
+	}
+}
+
+// WithTracerProvider sets the provider of the tracer used to create a span for each
+// API call and for each HTTP request it sends. By default, the global tracer
+// provider is used.
+func WithTracerProvider(provider trace.TracerProvider) ClientOption {
+	return func(c *Client) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithCircuitBreaker sets the circuit breaker used to stop sending requests while the
+// API is failing or in maintenance. The breaker is shared by all of the client's tokens.
+func WithCircuitBreaker(breaker *rest.CircuitBreaker) ClientOption {
+	return func(c *Client) {
+		c.breaker = breaker
+	}
+}
+
+// WithCoalescing sets whether identical requests that are in progress at the same
+// time, and use the same token, share a single round trip to the server. Coalescing
+// is on by default.
+func WithCoalescing(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.coalescer = nil
+		if enabled {
+			c.coalescer = rest.NewCoalescer()
+		}
+	}
+}
+
+// WithConcurrency sets the maximum number of requests sent at the same time when
+// retrieving a batch of players or clans
+func WithConcurrency(concurrency int) ClientOption {
+	return func(c *Client) {
+		c.concurrency = concurrency
+	}
+}
+
+// WithTokens sets the tokens used by the client. Requests are spread across the
+// tokens, and a token that is throttled or not valid for the current IP address is
+// taken out of rotation for a while.
+func WithTokens(tokens ...string) ClientOption {
+	return func(c *Client) {
+		c.keys.set(tokens...)
+	}
+}
+
+// WithKeySelection sets how the client chooses which of its tokens to use next
+func WithKeySelection(selection KeySelection) ClientOption {
+	return func(c *Client) {
+		c.keys.selection = selection
+	}
+}
+
+// WithKeyBenchTime sets how long a throttled or rejected token is taken out of rotation
+func WithKeyBenchTime(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.keys.benchTime = d
+	}
+}
+
+// WithLogger sets the logger used by the client. By default, nothing is logged.
+func WithLogger(logger rest.Logger) ClientOption {
+	return func(c *Client) {
+		c.log = logger
+	}
+}
+
+// NewClient creates a new client that uses the given token for authentication.
+// If no base URL is provided, the one from the configuration file is used.
+func NewClient(token string, options ...ClientOption) *Client {
+	c := &Client{
+		keys:            newKeyPool(token),
+		baseURL:         config.Data.BaseURL,
+		transportConfig: rest.DefaultTransportConfig(),
+		concurrency:     defaultConcurrency,
+		coalescer:       rest.NewCoalescer(),
+		log:             rest.NopLogger{},
+		restClients:     make(map[string]rest.Client),
+	}
+	for _, option := range options {
+		option(c)
+	}
+
+	if c.tracerProvider == nil {
+		c.tracerProvider = otel.GetTracerProvider()
+	}
+	c.tracer = c.tracerProvider.Tracer(tracerName)
+
+	// Create the HTTP client shared by all requests sent by the client
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{}
+		if c.transport == nil {
+			c.transport = rest.NewTransport(c.transportConfig)
+		}
+	}
+	if c.transport != nil {
+		httpClient := *c.httpClient
+		httpClient.Transport = c.transport
+		c.httpClient = &httpClient
+	}
+	return c
+}
+
+// SetToken sets the token to be used on requests sent by the client
+func (c *Client) SetToken(token string) {
+	c.SetTokens(token)
+}
+
+// SetTokens sets the tokens to be used on requests sent by the client
+func (c *Client) SetTokens(tokens ...string) {
+	c.keys.set(tokens...)
+	c.mu.Lock()
+	c.restClients = make(map[string]rest.Client)
+	c.mu.Unlock()
+}
+
+// restClient returns the REST client used to send requests with the given token. A
+// REST client is created the first time a token is used and reused afterwards.
+func (c *Client) restClient(token string) rest.Client {
+	c.mu.RLock()
+	client, ok := c.restClients[token]
+	c.mu.RUnlock()
+	if ok {
+		return client
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if client, ok := c.restClients[token]; ok {
+		return client
+	}
+	headers := rest.Headers{"Authorization": "Bearer " + token}
+	for k, v := range defaultHeaders {
+		headers[k] = v
+	}
+	options := []rest.Option{
+		rest.WithLogger(c.log),
+		rest.WithHTTPClient(c.httpClient),
+		rest.WithTracerProvider(c.tracerProvider),
+		rest.WithCoalescing(c.coalescer),
+	}
+	if c.limiter != nil {
+		options = append(options, rest.WithRateLimiter(c.limiter))
+	}
+	if c.retry != nil {
+		options = append(options, rest.WithRetryPolicy(*c.retry))
+	}
+	if c.cache != nil {
+		options = append(options, rest.WithCache(c.cache))
+	}
+	if len(c.interceptors) > 0 {
+		options = append(options, rest.WithInterceptors(c.interceptors...))
+	}
+	if c.metrics != nil {
+		options = append(options, rest.WithMetrics(c.metrics))
+	}
+	if c.breaker != nil {
+		options = append(options, rest.WithCircuitBreaker(c.breaker))
+	}
+	client = rest.NewClient(headers, nil, options...)
+	c.restClients[token] = client
+	return client
+}
@@ -0,0 +1,66 @@
+package coc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/clashgolang/coc/pkg/rest"
+)
+
+// newPlayerServer starts a server that returns a player for any tag it is asked for
+func newPlayerServer(b *testing.B) *httptest.Server {
+	b.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tag := strings.TrimPrefix(r.URL.Path, "/players/")
+		fmt.Fprintf(w, `{"tag":%q,"name":"player","expLevel":200,"trophies":5000}`, tag)
+	}))
+	b.Cleanup(server.Close)
+	return server
+}
+
+// BenchmarkGetPlayers measures the throughput of fetching 250 players at once with
+// GetPlayers, for a few sizes of the connection pool and numbers of concurrent
+// requests. All of the requests share the client's transport, so that connections to
+// the server are reused up to the pool size.
+func BenchmarkGetPlayers(b *testing.B) {
+	server := newPlayerServer(b)
+	tags := make([]string, 250)
+	for i := range tags {
+		tags[i] = fmt.Sprintf("#P%d", i)
+	}
+
+	benchmarks := []struct {
+		maxIdleConnsPerHost int
+		concurrency         int
+	}{
+		{maxIdleConnsPerHost: 2, concurrency: 10},
+		{maxIdleConnsPerHost: 2, concurrency: 100},
+		{maxIdleConnsPerHost: 100, concurrency: 10},
+		{maxIdleConnsPerHost: 100, concurrency: 100},
+		{maxIdleConnsPerHost: 250, concurrency: 250},
+	}
+	for _, bm := range benchmarks {
+		name := fmt.Sprintf("idle=%d/concurrency=%d", bm.maxIdleConnsPerHost, bm.concurrency)
+		b.Run(name, func(b *testing.B) {
+			config := rest.DefaultTransportConfig()
+			config.MaxIdleConns = bm.maxIdleConnsPerHost
+			config.MaxIdleConnsPerHost = bm.maxIdleConnsPerHost
+			client := NewClient("token", WithBaseURL(server.URL), WithTransportConfig(config), WithConcurrency(bm.concurrency))
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for _, result := range client.GetPlayers(context.Background(), tags) {
+					if result.Err != nil {
+						b.Fatal(result.Err)
+					}
+				}
+			}
+			b.ReportMetric(float64(b.N*len(tags))/b.Elapsed().Seconds(), "players/s")
+		})
+	}
+}
@@ -1,11 +1,11 @@
 package coc
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 
-	"github.com/clashgolang/coc/pkg/config"
-	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // ClanWarLeague is a reference to a given clan war league
@@ -67,15 +67,35 @@ func (lw ClanWarLeagueWar) String() string {
 
 // GetClanWarLeagueGroup retrieves information about clan's current clan war league group
 func GetClanWarLeagueGroup(clanTag string) (*ClanWarLeagueGroup, error) {
+	return defaultClient.GetClanWarLeagueGroup(clanTag)
+}
+
+// GetClanWarLeagueGroupContext retrieves information about clan's current clan war league group
+func GetClanWarLeagueGroupContext(ctx context.Context, clanTag string) (*ClanWarLeagueGroup, error) {
+	return defaultClient.GetClanWarLeagueGroupContext(ctx, clanTag)
+}
+
+// GetClanWarLeagueGroup retrieves information about clan's current clan war league group
+//
+// GetClanWarLeagueGroup uses context.Background internally; to specify the context, use
+// GetClanWarLeagueGroupContext.
+func (c *Client) GetClanWarLeagueGroup(clanTag string) (*ClanWarLeagueGroup, error) {
+	return c.GetClanWarLeagueGroupContext(context.Background(), clanTag)
+}
+
+// GetClanWarLeagueGroupContext retrieves information about clan's current clan war league group
+func (c *Client) GetClanWarLeagueGroupContext(ctx context.Context, clanTag string) (*ClanWarLeagueGroup, error) {
+	ctx, span := c.startSpan(ctx, "GetClanWarLeagueGroup", "/clans/{clanTag}", attribute.String("coc.tag", clanTag))
+	defer span.End()
+
 	var sb strings.Builder
 	sb.Grow(100)
-	sb.WriteString(config.Data.BaseURL)
+	sb.WriteString(c.baseURL)
 	sb.WriteString("/clans/")
 	sb.WriteString(fmtTag(clanTag))
 	url := sb.String()
-	log.Trace(url)
 
-	body, err := get(url, nil)
+	body, err := c.get(ctx, url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -87,7 +107,7 @@ func GetClanWarLeagueGroup(clanTag string) (*ClanWarLeagueGroup, error) {
 	var resp respType
 	err = json.Unmarshal(body, &resp)
 	if err != nil {
-		log.Debug("failed to parse the json response")
+		c.log.Debug("failed to parse the json response", "error", err)
 		return nil, err
 	}
 
@@ -96,16 +116,36 @@ func GetClanWarLeagueGroup(clanTag string) (*ClanWarLeagueGroup, error) {
 
 // GetClanWarLeagueWar retrieves information about individual clan war league war
 func GetClanWarLeagueWar(clanTag string) (*ClanWarLeagueWar, error) {
+	return defaultClient.GetClanWarLeagueWar(clanTag)
+}
+
+// GetClanWarLeagueWarContext retrieves information about individual clan war league war
+func GetClanWarLeagueWarContext(ctx context.Context, clanTag string) (*ClanWarLeagueWar, error) {
+	return defaultClient.GetClanWarLeagueWarContext(ctx, clanTag)
+}
+
+// GetClanWarLeagueWar retrieves information about individual clan war league war
+//
+// GetClanWarLeagueWar uses context.Background internally; to specify the context, use
+// GetClanWarLeagueWarContext.
+func (c *Client) GetClanWarLeagueWar(clanTag string) (*ClanWarLeagueWar, error) {
+	return c.GetClanWarLeagueWarContext(context.Background(), clanTag)
+}
+
+// GetClanWarLeagueWarContext retrieves information about individual clan war league war
+func (c *Client) GetClanWarLeagueWarContext(ctx context.Context, clanTag string) (*ClanWarLeagueWar, error) {
+	ctx, span := c.startSpan(ctx, "GetClanWarLeagueWar", "/clanswarleagues/wars/{warTag}/currentwar", attribute.String("coc.tag", clanTag))
+	defer span.End()
+
 	var sb strings.Builder
 	sb.Grow(100)
-	sb.WriteString(config.Data.BaseURL)
+	sb.WriteString(c.baseURL)
 	sb.WriteString("/clanswarleagues/wars/")
 	sb.WriteString(fmtTag(clanTag))
 	sb.WriteString("/currentwar")
 	url := sb.String()
-	log.Trace(url)
 
-	body, err := get(url, nil)
+	body, err := c.get(ctx, url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -117,7 +157,7 @@ func GetClanWarLeagueWar(clanTag string) (*ClanWarLeagueWar, error) {
 	var resp respType
 	err = json.Unmarshal(body, &resp)
 	if err != nil {
-		log.Debug("failed to parse the json response")
+		c.log.Debug("failed to parse the json response", "error", err)
 		return nil, err
 	}
 
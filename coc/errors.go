@@ -1,9 +1,15 @@
 package coc
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/clashgolang/coc/pkg/rest"
+)
 
 var (
-	ErrClanNotFound = errors.New("clan not found")
-	ErrNotInWar     = errors.New("clan is not in a war")
-	ErrTagMissing   = errors.New("no tag provided")
+	ErrClanNotFound   = fmt.Errorf("clan %w", rest.ErrNotFound)
+	ErrPlayerNotFound = fmt.Errorf("player %w", rest.ErrNotFound)
+	ErrNotInWar       = errors.New("clan is not in a war")
+	ErrTagMissing     = errors.New("no tag provided")
 )
@@ -0,0 +1,81 @@
+package coc
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// GoldPassSeason is the current season of the gold pass.
+type GoldPassSeason struct {
+	StartTime CoCTime `json:"startTime"`
+	EndTime   CoCTime `json:"endTime"`
+}
+
+// String returns a string representation of a gold pass season
+func (gps GoldPassSeason) String() string {
+	b, _ := json.Marshal(gps)
+	return string(b)
+}
+
+// TimeRemaining returns how long is left until the season ends, or zero if it has
+// already ended
+func (gps GoldPassSeason) TimeRemaining() time.Duration {
+	return gps.TimeRemainingAt(time.Now())
+}
+
+// TimeRemainingAt returns how long is left in the season at the given time, or zero
+// if the season has ended by then
+func (gps GoldPassSeason) TimeRemainingAt(t time.Time) time.Duration {
+	remaining := time.Time(gps.EndTime).Sub(t)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// GetGoldPassSeason retrieves the current gold pass season
+func GetGoldPassSeason() (*GoldPassSeason, error) {
+	return defaultClient.GetGoldPassSeason()
+}
+
+// GetGoldPassSeasonContext retrieves the current gold pass season
+func GetGoldPassSeasonContext(ctx context.Context) (*GoldPassSeason, error) {
+	return defaultClient.GetGoldPassSeasonContext(ctx)
+}
+
+// GetGoldPassSeason retrieves the current gold pass season
+//
+// GetGoldPassSeason uses context.Background internally; to specify the context, use
+// GetGoldPassSeasonContext.
+func (c *Client) GetGoldPassSeason() (*GoldPassSeason, error) {
+	return c.GetGoldPassSeasonContext(context.Background())
+}
+
+// GetGoldPassSeasonContext retrieves the current gold pass season
+func (c *Client) GetGoldPassSeasonContext(ctx context.Context) (*GoldPassSeason, error) {
+	ctx, span := c.startSpan(ctx, "GetGoldPassSeason", "/goldpass/seasons/current")
+	defer span.End()
+
+	// Build the URL
+	var sb strings.Builder
+	sb.Grow(100)
+	sb.WriteString(c.baseURL)
+	sb.WriteString("/goldpass/seasons/current")
+	url := sb.String()
+
+	// Get the gold pass season
+	body, err := c.get(ctx, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var season GoldPassSeason
+	if err := json.Unmarshal(body, &season); err != nil {
+		c.log.Debug("failed to parse the json response", "error", err)
+		return nil, err
+	}
+
+	// Return the gold pass season
+	return &season, nil
+}
@@ -0,0 +1,293 @@
+package coc
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"strings"
+
+	"github.com/clashgolang/coc/pkg/rest"
+)
+
+const (
+	defaultPortalURL      = "https://developer.clashofclans.com"
+	defaultKeyName        = "coc"
+	defaultKeyDescription = "Created by the clashgolang/coc key manager"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid developer portal email or password")
+	ErrUnknownIP          = errors.New("unable to determine the current IP address")
+)
+
+// KeyManager manages the API keys of a developer account on the Clash of Clans
+// developer portal. Since keys are locked to an IP address, the key manager
+// revokes the keys it created for other addresses and creates keys for the
+// current one.
+type KeyManager struct {
+	email          string
+	password       string
+	portalURL      string
+	keyName        string
+	keyDescription string
+	keyCount       int
+	httpClient     *http.Client
+	resolveIP      func(ctx context.Context, temporaryToken string) (string, error)
+	log            rest.Logger
+}
+
+// KeyManagerOption configures an optional setting on a key manager
+type KeyManagerOption func(*KeyManager)
+
+// WithPortalURL sets the base URL of the developer portal
+func WithPortalURL(portalURL string) KeyManagerOption {
+	return func(km *KeyManager) {
+		km.portalURL = portalURL
+	}
+}
+
+// WithKeyName sets the name given to the keys created by the key manager. Only keys
+// with this name are revoked by the key manager.
+func WithKeyName(name string) KeyManagerOption {
+	return func(km *KeyManager) {
+		km.keyName = name
+	}
+}
+
+// WithKeyCount sets the number of keys the key manager makes available for the
+// current IP address
+func WithKeyCount(count int) KeyManagerOption {
+	return func(km *KeyManager) {
+		km.keyCount = count
+	}
+}
+
+// WithPortalHTTPClient sets the HTTP client used to send requests to the developer portal
+func WithPortalHTTPClient(httpClient *http.Client) KeyManagerOption {
+	return func(km *KeyManager) {
+		km.httpClient = httpClient
+	}
+}
+
+// WithIPResolver sets the function used to find the current outbound IP address. By
+// default, the address is read from the temporary token returned when logging in to
+// the developer portal.
+func WithIPResolver(resolve func(ctx context.Context) (string, error)) KeyManagerOption {
+	return func(km *KeyManager) {
+		km.resolveIP = func(ctx context.Context, _ string) (string, error) {
+			return resolve(ctx)
+		}
+	}
+}
+
+// WithKeyManagerLogger sets the logger used by the key manager
+func WithKeyManagerLogger(logger rest.Logger) KeyManagerOption {
+	return func(km *KeyManager) {
+		km.log = logger
+	}
+}
+
+// NewKeyManager creates a key manager for the developer account with the given
+// email and password.
+func NewKeyManager(email string, password string, options ...KeyManagerOption) *KeyManager {
+	km := &KeyManager{
+		email:          email,
+		password:       password,
+		portalURL:      defaultPortalURL,
+		keyName:        defaultKeyName,
+		keyDescription: defaultKeyDescription,
+		keyCount:       1,
+		httpClient:     http.DefaultClient,
+		resolveIP:      ipFromTemporaryToken,
+		log:            rest.NopLogger{},
+	}
+	for _, option := range options {
+		option(km)
+	}
+	return km
+}
+
+// portalKey is an API key as returned by the developer portal
+type portalKey struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	CidrRanges  []string `json:"cidrRanges"`
+	Scopes      []string `json:"scopes"`
+	Key         string   `json:"key"`
+}
+
+// Keys logs in to the developer portal and returns keys that are valid for the
+// current IP address. Keys created by the key manager for other IP addresses are
+// revoked, and new keys are created until the configured number of keys exist.
+func (km *KeyManager) Keys(ctx context.Context) ([]string, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+	httpClient := *km.httpClient
+	httpClient.Jar = jar
+
+	// Log in, which starts a session used by the remaining requests
+	var login struct {
+		TemporaryAPIToken string `json:"temporaryAPIToken"`
+	}
+	creds := map[string]string{"email": km.email, "password": km.password}
+	if err := km.post(ctx, &httpClient, "/api/login", creds, &login); err != nil {
+		var httpErr rest.ErrHttp
+		if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusForbidden {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+	ip, err := km.resolveIP(ctx, login.TemporaryAPIToken)
+	if err != nil {
+		return nil, err
+	}
+	km.log.Debug("found current IP address", "ip", ip)
+
+	// Keep the keys for the current IP address, and revoke our keys for other addresses
+	var list struct {
+		Keys []portalKey `json:"keys"`
+	}
+	if err := km.post(ctx, &httpClient, "/api/apikey/list", struct{}{}, &list); err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, km.keyCount)
+	for _, key := range list.Keys {
+		if key.Name != km.keyName {
+			continue
+		}
+		if allowsIP(key.CidrRanges, ip) && len(keys) < km.keyCount {
+			keys = append(keys, key.Key)
+			continue
+		}
+		km.log.Debug("revoking key", "id", key.ID, "cidrRanges", key.CidrRanges)
+		revoke := map[string]string{"id": key.ID}
+		if err := km.post(ctx, &httpClient, "/api/apikey/revoke", revoke, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	// Create any keys that are still needed
+	for len(keys) < km.keyCount {
+		km.log.Debug("creating key", "ip", ip)
+		create := portalKey{
+			Name:        km.keyName,
+			Description: km.keyDescription,
+			CidrRanges:  []string{ip},
+			Scopes:      []string{"clash"},
+		}
+		var created struct {
+			Key portalKey `json:"key"`
+		}
+		if err := km.post(ctx, &httpClient, "/api/apikey/create", create, &created); err != nil {
+			return nil, err
+		}
+		keys = append(keys, created.Key.Key)
+	}
+
+	return keys, nil
+}
+
+// Apply gets keys that are valid for the current IP address and sets them as the
+// tokens used by the client.
+func (km *KeyManager) Apply(ctx context.Context, c *Client) error {
+	keys, err := km.Keys(ctx)
+	if err != nil {
+		return err
+	}
+	c.SetTokens(keys...)
+	return nil
+}
+
+// post sends a JSON request to the developer portal and parses the JSON response
+// into result, if it is not nil.
+func (km *KeyManager) post(ctx context.Context, httpClient *http.Client, path string, request interface{}, result interface{}) error {
+	b, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", km.portalURL+path, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		km.log.Error("developer portal request failed", "path", path, "statusCode", resp.StatusCode)
+		return rest.ErrHttp{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		km.log.Debug("failed to parse the json response", "error", err)
+		return err
+	}
+	return nil
+}
+
+// ipFromTemporaryToken reads the current IP address from the claims of the temporary
+// token returned by the developer portal when logging in.
+func ipFromTemporaryToken(_ context.Context, temporaryToken string) (string, error) {
+	parts := strings.Split(temporaryToken, ".")
+	if len(parts) != 3 {
+		return "", ErrUnknownIP
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnknownIP, err)
+	}
+
+	var claims struct {
+		Limits []struct {
+			Type  string   `json:"type"`
+			Cidrs []string `json:"cidrs"`
+		} `json:"limits"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnknownIP, err)
+	}
+	for _, limit := range claims.Limits {
+		if limit.Type == "client" && len(limit.Cidrs) > 0 {
+			return strings.TrimSuffix(limit.Cidrs[0], "/32"), nil
+		}
+	}
+	return "", ErrUnknownIP
+}
+
+// allowsIP returns whether the CIDR ranges of a key include the given IP address
+func allowsIP(cidrRanges []string, ip string) bool {
+	addr := net.ParseIP(ip)
+	for _, cidr := range cidrRanges {
+		if cidr == ip {
+			return true
+		}
+		if _, network, err := net.ParseCIDR(cidr); err == nil && network.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,249 @@
+package coc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+const (
+	testEmail    = "dev@example.com"
+	testPassword = "secret"
+	testIP       = "203.0.113.7"
+	sessionName  = "session"
+)
+
+// fakePortal is a stand-in for the developer portal endpoints used by the key manager
+type fakePortal struct {
+	mu      sync.Mutex
+	keys    []portalKey
+	revoked []string
+	created []portalKey
+}
+
+// newFakePortal starts a fake developer portal holding the given keys
+func newFakePortal(t *testing.T, keys []portalKey) (*fakePortal, *httptest.Server) {
+	t.Helper()
+	portal := &fakePortal{keys: keys}
+	server := httptest.NewServer(portal)
+	t.Cleanup(server.Close)
+	return portal, server
+}
+
+func (p *fakePortal) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if r.URL.Path == "/api/login" {
+		var creds map[string]string
+		json.NewDecoder(r.Body).Decode(&creds)
+		if creds["email"] != testEmail || creds["password"] != testPassword {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"status":{"code":403,"message":"forbidden"}}`))
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: sessionName, Value: "ok", Path: "/"})
+		json.NewEncoder(w).Encode(map[string]string{"temporaryAPIToken": testToken(testIP)})
+		return
+	}
+
+	// The remaining endpoints need the session started by logging in
+	if cookie, err := r.Cookie(sessionName); err != nil || cookie.Value != "ok" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	switch r.URL.Path {
+	case "/api/apikey/list":
+		json.NewEncoder(w).Encode(map[string][]portalKey{"keys": p.keys})
+	case "/api/apikey/revoke":
+		var revoke map[string]string
+		json.NewDecoder(r.Body).Decode(&revoke)
+		for i, key := range p.keys {
+			if key.ID == revoke["id"] {
+				p.keys = append(p.keys[:i], p.keys[i+1:]...)
+				break
+			}
+		}
+		p.revoked = append(p.revoked, revoke["id"])
+		w.Write([]byte(`{}`))
+	case "/api/apikey/create":
+		var key portalKey
+		json.NewDecoder(r.Body).Decode(&key)
+		key.ID = fmt.Sprintf("created-%d", len(p.created)+1)
+		key.Key = "token-" + key.ID
+		p.keys = append(p.keys, key)
+		p.created = append(p.created, key)
+		json.NewEncoder(w).Encode(map[string]portalKey{"key": key})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// testToken returns a temporary token shaped like the one returned by the developer
+// portal, limited to the given IP address
+func testToken(ip string) string {
+	encode := func(v interface{}) string {
+		b, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	header := map[string]string{"typ": "JWT", "alg": "HS512", "kid": "28a318f7-0000-a1eb-7fa1-2c7433c6cca5"}
+	claims := map[string]interface{}{
+		"iss":    "supercell",
+		"aud":    "supercell:gameapi",
+		"jti":    "4ab5e3b0-7e2a-4f1b-a2cd-5b4c0bdf8d36",
+		"iat":    1697443200,
+		"exp":    1697446800,
+		"sub":    "developer/2f3c4e5a-0000-4b8e-9c2d-7a1b2c3d4e5f",
+		"scopes": []string{"clash"},
+		"limits": []map[string]interface{}{
+			{"tier": "developer/bronze", "type": "throttling"},
+			{"cidrs": []string{ip + "/32"}, "type": "client"},
+			{"origins": []string{"developer.clashofclans.com"}, "type": "cors"},
+		},
+	}
+	return encode(header) + "." + encode(claims) + ".c2lnbmF0dXJl"
+}
+
+func TestKeyManagerKeys(t *testing.T) {
+	tests := []struct {
+		name        string
+		keyCount    int
+		keys        []portalKey
+		wantKeys    []string
+		wantRevoked []string
+		wantCreated int
+	}{
+		{
+			name:        "no keys",
+			keyCount:    2,
+			wantKeys:    []string{"token-created-1", "token-created-2"},
+			wantCreated: 2,
+		},
+		{
+			name:     "keeps a matching key and revokes a stale one",
+			keyCount: 3,
+			keys: []portalKey{
+				{ID: "stale", Name: defaultKeyName, CidrRanges: []string{"198.51.100.1"}, Key: "token-stale"},
+				{ID: "match", Name: defaultKeyName, CidrRanges: []string{testIP}, Key: "token-match"},
+				{ID: "other", Name: "someone else", CidrRanges: []string{"198.51.100.1"}, Key: "token-other"},
+			},
+			wantKeys:    []string{"token-match", "token-created-1", "token-created-2"},
+			wantRevoked: []string{"stale"},
+			wantCreated: 2,
+		},
+		{
+			name:     "keeps a key whose range includes the address",
+			keyCount: 1,
+			keys: []portalKey{
+				{ID: "range", Name: defaultKeyName, CidrRanges: []string{"203.0.113.0/24"}, Key: "token-range"},
+			},
+			wantKeys: []string{"token-range"},
+		},
+		{
+			name:     "revokes matching keys beyond the key count",
+			keyCount: 1,
+			keys: []portalKey{
+				{ID: "first", Name: defaultKeyName, CidrRanges: []string{testIP}, Key: "token-first"},
+				{ID: "second", Name: defaultKeyName, CidrRanges: []string{testIP}, Key: "token-second"},
+			},
+			wantKeys:    []string{"token-first"},
+			wantRevoked: []string{"second"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			portal, server := newFakePortal(t, tt.keys)
+			km := NewKeyManager(testEmail, testPassword, WithPortalURL(server.URL), WithKeyCount(tt.keyCount))
+
+			keys, err := km.Keys(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(keys, tt.wantKeys) {
+				t.Errorf("got keys %v, want %v", keys, tt.wantKeys)
+			}
+			if !reflect.DeepEqual(portal.revoked, tt.wantRevoked) {
+				t.Errorf("got revoked keys %v, want %v", portal.revoked, tt.wantRevoked)
+			}
+			if len(portal.created) != tt.wantCreated {
+				t.Fatalf("got %d created keys, want %d", len(portal.created), tt.wantCreated)
+			}
+			for _, key := range portal.created {
+				if key.Name != defaultKeyName {
+					t.Errorf("created key has name %q, want %q", key.Name, defaultKeyName)
+				}
+				if !reflect.DeepEqual(key.CidrRanges, []string{testIP}) {
+					t.Errorf("created key has CIDR ranges %v, want [%s]", key.CidrRanges, testIP)
+				}
+				if !reflect.DeepEqual(key.Scopes, []string{"clash"}) {
+					t.Errorf("created key has scopes %v, want [clash]", key.Scopes)
+				}
+			}
+		})
+	}
+}
+
+func TestKeyManagerInvalidCredentials(t *testing.T) {
+	portal, server := newFakePortal(t, nil)
+	km := NewKeyManager(testEmail, "wrong", WithPortalURL(server.URL))
+
+	if _, err := km.Keys(context.Background()); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("got %v, want ErrInvalidCredentials", err)
+	}
+	if len(portal.created) != 0 {
+		t.Errorf("got %d created keys, want none", len(portal.created))
+	}
+}
+
+func TestKeyManagerApply(t *testing.T) {
+	_, server := newFakePortal(t, nil)
+	km := NewKeyManager(testEmail, testPassword, WithPortalURL(server.URL), WithKeyCount(2))
+	client := NewClient("")
+
+	if err := km.Apply(context.Background(), client); err != nil {
+		t.Fatal(err)
+	}
+	if got := client.keys.size(); got != 2 {
+		t.Errorf("client has %d tokens, want 2", got)
+	}
+}
+
+func TestIPFromTemporaryToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   string
+		want    string
+		wantErr error
+	}{
+		{name: "portal token", token: testToken(testIP), want: testIP},
+		{name: "not a JWT", token: "abc", wantErr: ErrUnknownIP},
+		{name: "bad payload", token: "a.!!!.c", wantErr: ErrUnknownIP},
+		{
+			name:    "no client limit",
+			token:   "e30." + base64.RawURLEncoding.EncodeToString([]byte(`{"limits":[{"type":"throttling"}]}`)) + ".c2ln",
+			wantErr: ErrUnknownIP,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ip, err := ipFromTemporaryToken(context.Background(), tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if ip != tt.want {
+				t.Errorf("got %q, want %q", ip, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package coc
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/clashgolang/coc/pkg/rest"
+)
+
+const (
+	// defaultBenchTime is how long a key is left out of rotation after it is throttled
+	// or rejected for the current IP address
+	defaultBenchTime = time.Minute
+)
+
+// KeySelection determines how the next key is chosen when a client has several tokens
+type KeySelection int
+
+const (
+	// RoundRobin uses each available key in turn
+	RoundRobin KeySelection = iota
+	// LeastRecentlyThrottled uses the available key that was throttled longest ago
+	LeastRecentlyThrottled
+)
+
+// keyPool is the set of tokens used by a client. Keys that are throttled or are not
+// valid for the current IP address are benched for a while, so requests are spread
+// over the remaining keys.
+type keyPool struct {
+	mu        sync.Mutex
+	keys      []*poolKey
+	next      int
+	selection KeySelection
+	benchTime time.Duration
+}
+
+// poolKey is a single token in a key pool
+type poolKey struct {
+	token         string
+	benchedUntil  time.Time
+	lastThrottled time.Time
+}
+
+// newKeyPool creates a key pool with the given tokens
+func newKeyPool(tokens ...string) *keyPool {
+	p := &keyPool{benchTime: defaultBenchTime}
+	p.set(tokens...)
+	return p
+}
+
+// set replaces the tokens in the key pool
+func (p *keyPool) set(tokens ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keys = make([]*poolKey, 0, len(tokens))
+	for _, token := range tokens {
+		if token != "" {
+			p.keys = append(p.keys, &poolKey{token: token})
+		}
+	}
+	p.next = 0
+}
+
+// size returns the number of tokens in the key pool
+func (p *keyPool) size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.keys)
+}
+
+// pick returns the token to use for the next request. If every key is benched, the
+// one that comes off the bench first is used.
+func (p *keyPool) pick() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.keys) == 0 {
+		return ""
+	}
+
+	now := time.Now()
+	var chosen *poolKey
+	switch p.selection {
+	case LeastRecentlyThrottled:
+		// Keys throttled equally long ago are used in turn, as with round robin
+		chosenAt := 0
+		for i := range p.keys {
+			at := (p.next + i) % len(p.keys)
+			key := p.keys[at]
+			if key.benchedUntil.After(now) {
+				continue
+			}
+			if chosen == nil || key.lastThrottled.Before(chosen.lastThrottled) {
+				chosen, chosenAt = key, at
+			}
+		}
+		if chosen != nil {
+			p.next = (chosenAt + 1) % len(p.keys)
+		}
+	default:
+		for i := range p.keys {
+			key := p.keys[(p.next+i)%len(p.keys)]
+			if !key.benchedUntil.After(now) {
+				chosen = key
+				p.next = (p.next + i + 1) % len(p.keys)
+				break
+			}
+		}
+	}
+
+	if chosen == nil {
+		chosen = p.keys[0]
+		for _, key := range p.keys[1:] {
+			if key.benchedUntil.Before(chosen.benchedUntil) {
+				chosen = key
+			}
+		}
+	}
+	return chosen.token
+}
+
+// report records the result of a request sent with the given token, and benches the
+// key if it was throttled or may not be used from this IP address. It returns whether
+// the key was benched.
+func (p *keyPool) report(token string, err error) bool {
+	if !errors.Is(err, rest.ErrThrottled) && !errors.Is(err, rest.ErrInvalidIP) {
+		return false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	for _, key := range p.keys {
+		if key.token == token {
+			key.benchedUntil = now.Add(p.benchTime)
+			key.lastThrottled = now
+			return true
+		}
+	}
+	return false
+}
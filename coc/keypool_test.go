@@ -0,0 +1,234 @@
+package coc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/clashgolang/coc/pkg/rest"
+)
+
+var (
+	throttled = rest.ErrHttp{StatusCode: http.StatusTooManyRequests, Reason: rest.ReasonThrottled}
+	invalidIP = rest.ErrHttp{StatusCode: http.StatusForbidden, Reason: rest.ReasonInvalidIP}
+)
+
+func TestKeyPoolPick(t *testing.T) {
+	tests := []struct {
+		name      string
+		selection KeySelection
+		benchTime time.Duration
+		benched   []string
+		want      []string
+	}{
+		{
+			name:      "round robin",
+			selection: RoundRobin,
+			want:      []string{"a", "b", "c", "a", "b", "c"},
+		},
+		{
+			name:      "round robin skips a benched key",
+			selection: RoundRobin,
+			benched:   []string{"b"},
+			want:      []string{"a", "c", "a", "c"},
+		},
+		{
+			name:      "round robin with every key benched",
+			selection: RoundRobin,
+			benched:   []string{"b", "c", "a"},
+			want:      []string{"b", "b"},
+		},
+		{
+			name:      "least recently throttled takes turns",
+			selection: LeastRecentlyThrottled,
+			want:      []string{"a", "b", "c", "a", "b", "c"},
+		},
+		{
+			name:      "least recently throttled skips a benched key",
+			selection: LeastRecentlyThrottled,
+			benched:   []string{"a"},
+			want:      []string{"b", "c", "b", "c"},
+		},
+		{
+			name:      "least recently throttled prefers keys never throttled",
+			selection: LeastRecentlyThrottled,
+			benchTime: -1,
+			benched:   []string{"a"},
+			want:      []string{"b", "c", "b", "c"},
+		},
+		{
+			name:      "least recently throttled off the bench in the order throttled",
+			selection: LeastRecentlyThrottled,
+			benchTime: -1,
+			benched:   []string{"c", "b", "a"},
+			want:      []string{"c", "c"},
+		},
+		{
+			name:      "least recently throttled with every key benched",
+			selection: LeastRecentlyThrottled,
+			benched:   []string{"c", "a", "b"},
+			want:      []string{"c", "c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := newKeyPool("a", "b", "c")
+			pool.selection = tt.selection
+			if tt.benchTime != 0 {
+				pool.benchTime = tt.benchTime
+			}
+			for _, token := range tt.benched {
+				if !pool.report(token, throttled) {
+					t.Fatalf("key %q was not benched", token)
+				}
+				time.Sleep(time.Millisecond)
+			}
+
+			var got []string
+			for range tt.want {
+				got = append(got, pool.pick())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got keys %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyPoolReport(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+		err   error
+		want  bool
+	}{
+		{name: "throttled", token: "a", err: throttled, want: true},
+		{name: "throttled reason", token: "a", err: rest.ErrHttp{StatusCode: http.StatusServiceUnavailable, Reason: rest.ReasonThrottled}, want: true},
+		{name: "invalid IP address", token: "a", err: invalidIP, want: true},
+		{name: "wrapped invalid IP address", token: "a", err: errors.Join(errors.New("get"), invalidIP), want: true},
+		{name: "invalid token", token: "a", err: rest.ErrHttp{StatusCode: http.StatusForbidden, Reason: rest.ReasonAccessDenied}},
+		{name: "not found", token: "a", err: rest.ErrHttp{StatusCode: http.StatusNotFound, Reason: rest.ReasonNotFound}},
+		{name: "server error", token: "a", err: rest.ErrHttp{StatusCode: http.StatusInternalServerError}},
+		{name: "network error", token: "a", err: errors.New("connection reset")},
+		{name: "unknown token", token: "z", err: throttled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := newKeyPool("a", "b")
+			if got := pool.report(tt.token, tt.err); got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+
+			// A benched key is left out until it comes off the bench
+			want := []string{"a", "b"}
+			if tt.want {
+				want = []string{"b", "b"}
+			}
+			got := []string{pool.pick(), pool.pick()}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got keys %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// newKeyServer starts a server that answers requests according to the token they use,
+// and records the tokens in the order they are received
+func newKeyServer(t *testing.T, responses map[string]int) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		mu.Lock()
+		tokens = append(tokens, token)
+		mu.Unlock()
+
+		switch responses[token] {
+		case http.StatusTooManyRequests:
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"reason":"requestThrottled"}`))
+		case http.StatusForbidden:
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"reason":"accessDenied.invalidIp"}`))
+		case http.StatusNotFound:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"reason":"notFound"}`))
+		default:
+			w.Write([]byte(`{"tag":"#ABC","name":"player"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), tokens...)
+	}
+}
+
+func TestClientSendFailsOver(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses map[string]int
+		wantErr   error
+		want      []string
+	}{
+		{
+			name:      "first key works",
+			responses: map[string]int{},
+			want:      []string{"a"},
+		},
+		{
+			name:      "throttled and invalid IP keys are skipped",
+			responses: map[string]int{"a": http.StatusTooManyRequests, "b": http.StatusForbidden},
+			want:      []string{"a", "b", "c"},
+		},
+		{
+			name:      "every key benched",
+			responses: map[string]int{"a": http.StatusTooManyRequests, "b": http.StatusForbidden, "c": http.StatusTooManyRequests},
+			wantErr:   rest.ErrThrottled,
+			want:      []string{"a", "b", "c"},
+		},
+		{
+			name:      "other errors are not sent again",
+			responses: map[string]int{"a": http.StatusNotFound},
+			wantErr:   rest.ErrNotFound,
+			want:      []string{"a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, tokens := newKeyServer(t, tt.responses)
+			client := NewClient("", WithBaseURL(server.URL), WithTokens("a", "b", "c"))
+
+			_, err := client.GetPlayerContext(context.Background(), "#ABC")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if got := tokens(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got tokens %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("benched keys are not used again", func(t *testing.T) {
+		server, tokens := newKeyServer(t, map[string]int{"a": http.StatusTooManyRequests})
+		client := NewClient("", WithBaseURL(server.URL), WithTokens("a", "b", "c"))
+
+		for i := 0; i < 3; i++ {
+			if _, err := client.GetPlayerContext(context.Background(), "#ABC"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		want := []string{"a", "b", "c", "b"}
+		if got := tokens(); !reflect.DeepEqual(got, want) {
+			t.Errorf("got tokens %v, want %v", got, want)
+		}
+	})
+}
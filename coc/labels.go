@@ -1,12 +1,9 @@
 package coc
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
-
-	"github.com/clashgolang/coc/pkg/config"
-	"github.com/clashgolang/coc/pkg/rest"
-	log "github.com/sirupsen/logrus"
 )
 
 // Label is a label for a clan or player.
@@ -23,53 +20,151 @@ func (l Label) String() string {
 }
 
 // GetClanLabels lists clan labels
-func GetClanLabels(qparms rest.QParms) ([]Label, error) {
+func GetClanLabels(opts ...RequestOption) ([]Label, error) {
+	return defaultClient.GetClanLabels(opts...)
+}
+
+// GetClanLabelsContext lists clan labels
+func GetClanLabelsContext(ctx context.Context, opts ...RequestOption) ([]Label, error) {
+	return defaultClient.GetClanLabelsContext(ctx, opts...)
+}
+
+// GetClanLabelsPage returns a single page of labels, along with the cursors of the
+// pages before and after it
+func GetClanLabelsPage(ctx context.Context, opts ...RequestOption) (*Page[Label], error) {
+	return defaultClient.GetClanLabelsPage(ctx, opts...)
+}
+
+// IterateClanLabels returns an iterator over the labels, fetching pages as they are
+// needed. At most maxItems items are returned; if maxItems is zero, every item is
+// returned.
+func IterateClanLabels(ctx context.Context, maxItems int, opts ...RequestOption) *Iterator[Label] {
+	return defaultClient.IterateClanLabels(ctx, maxItems, opts...)
+}
+
+// GetClanLabels lists clan labels
+//
+// GetClanLabels uses context.Background internally; to specify the context, use
+// GetClanLabelsContext.
+func (c *Client) GetClanLabels(opts ...RequestOption) ([]Label, error) {
+	return c.GetClanLabelsContext(context.Background(), opts...)
+}
+
+// GetClanLabelsContext lists clan labels
+func (c *Client) GetClanLabelsContext(ctx context.Context, opts ...RequestOption) ([]Label, error) {
+	page, err := c.GetClanLabelsPage(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+// GetClanLabelsPage returns a single page of labels, along with the cursors of the
+// pages before and after it
+func (c *Client) GetClanLabelsPage(ctx context.Context, opts ...RequestOption) (*Page[Label], error) {
+	ctx, span := c.startSpan(ctx, "GetClanLabels", "/labels/clans")
+	defer span.End()
+
 	var sb strings.Builder
 	sb.Grow(100)
-	sb.WriteString(config.Data.BaseURL)
+	sb.WriteString(c.baseURL)
 	sb.WriteString("/labels/clans/")
 
-	body, err := get(sb.String(), nil)
+	body, err := c.get(ctx, sb.String(), requestParms(opts))
 	if err != nil {
 		return nil, err
 	}
 
-	// Parse into an array of clans
-	type respType struct {
-		Labels []Label `json:"items"`
-	}
-	var resp respType
-	err = json.Unmarshal(body, &resp)
-	if err != nil {
-		log.Debug("failed to parse the json response")
+	// Parse into a page of labels
+	var page Page[Label]
+	if err := json.Unmarshal(body, &page); err != nil {
+		c.log.Debug("failed to parse the json response", "error", err)
 		return nil, err
 	}
 
-	return resp.Labels, nil
+	return &page, nil
+}
+
+// IterateClanLabels returns an iterator over the labels, fetching pages as they are
+// needed. At most maxItems items are returned; if maxItems is zero, every item is
+// returned.
+func (c *Client) IterateClanLabels(ctx context.Context, maxItems int, opts ...RequestOption) *Iterator[Label] {
+	return newIterator(ctx, maxItems, opts, func(ctx context.Context, opts ...RequestOption) (*Page[Label], error) {
+		return c.GetClanLabelsPage(ctx, opts...)
+	})
 }
 
 // GetPlayerLabels lists player labels
-func GetPlayerLabels(qparms rest.QParms) ([]Label, error) {
+func GetPlayerLabels(opts ...RequestOption) ([]Label, error) {
+	return defaultClient.GetPlayerLabels(opts...)
+}
+
+// GetPlayerLabelsContext lists player labels
+func GetPlayerLabelsContext(ctx context.Context, opts ...RequestOption) ([]Label, error) {
+	return defaultClient.GetPlayerLabelsContext(ctx, opts...)
+}
+
+// GetPlayerLabelsPage returns a single page of labels, along with the cursors of the
+// pages before and after it
+func GetPlayerLabelsPage(ctx context.Context, opts ...RequestOption) (*Page[Label], error) {
+	return defaultClient.GetPlayerLabelsPage(ctx, opts...)
+}
+
+// IteratePlayerLabels returns an iterator over the labels, fetching pages as they
+// are needed. At most maxItems items are returned; if maxItems is zero, every item
+// is returned.
+func IteratePlayerLabels(ctx context.Context, maxItems int, opts ...RequestOption) *Iterator[Label] {
+	return defaultClient.IteratePlayerLabels(ctx, maxItems, opts...)
+}
+
+// GetPlayerLabels lists player labels
+//
+// GetPlayerLabels uses context.Background internally; to specify the context, use
+// GetPlayerLabelsContext.
+func (c *Client) GetPlayerLabels(opts ...RequestOption) ([]Label, error) {
+	return c.GetPlayerLabelsContext(context.Background(), opts...)
+}
+
+// GetPlayerLabelsContext lists player labels
+func (c *Client) GetPlayerLabelsContext(ctx context.Context, opts ...RequestOption) ([]Label, error) {
+	page, err := c.GetPlayerLabelsPage(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+// GetPlayerLabelsPage returns a single page of labels, along with the cursors of the
+// pages before and after it
+func (c *Client) GetPlayerLabelsPage(ctx context.Context, opts ...RequestOption) (*Page[Label], error) {
+	ctx, span := c.startSpan(ctx, "GetPlayerLabels", "/labels/players")
+	defer span.End()
+
 	var sb strings.Builder
 	sb.Grow(100)
-	sb.WriteString(config.Data.BaseURL)
+	sb.WriteString(c.baseURL)
 	sb.WriteString("/labels/players/")
 
-	body, err := get(sb.String(), nil)
+	body, err := c.get(ctx, sb.String(), requestParms(opts))
 	if err != nil {
 		return nil, err
 	}
 
-	// Parse into an array of clans
-	type respType struct {
-		Labels []Label `json:"items"`
-	}
-	var resp respType
-	err = json.Unmarshal(body, &resp)
-	if err != nil {
-		log.Debug("failed to parse the json response")
+	// Parse into a page of labels
+	var page Page[Label]
+	if err := json.Unmarshal(body, &page); err != nil {
+		c.log.Debug("failed to parse the json response", "error", err)
 		return nil, err
 	}
 
-	return resp.Labels, nil
+	return &page, nil
+}
+
+// IteratePlayerLabels returns an iterator over the labels, fetching pages as they
+// are needed. At most maxItems items are returned; if maxItems is zero, every item
+// is returned.
+func (c *Client) IteratePlayerLabels(ctx context.Context, maxItems int, opts ...RequestOption) *Iterator[Label] {
+	return newIterator(ctx, maxItems, opts, func(ctx context.Context, opts ...RequestOption) (*Page[Label], error) {
+		return c.GetPlayerLabelsPage(ctx, opts...)
+	})
 }
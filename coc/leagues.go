@@ -1,12 +1,11 @@
 package coc
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 
-	"github.com/clashgolang/coc/pkg/config"
-	"github.com/clashgolang/coc/pkg/rest"
-	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // League lists leagues
@@ -65,17 +64,61 @@ func (wl WarLeague) String() string {
 	return string(b)
 }
 
+// BuilderBaseLeague is information about a builder base league.
+type BuilderBaseLeague struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// String returns a string representation of a builder base league
+func (bl BuilderBaseLeague) String() string {
+	b, _ := json.Marshal(bl)
+	return string(b)
+}
+
+// CapitalLeague is information about a clan capital league.
+type CapitalLeague struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// String returns a string representation of a capital league
+func (cl CapitalLeague) String() string {
+	b, _ := json.Marshal(cl)
+	return string(b)
+}
+
 // GetLeague gets the league information
 func GetLeague(leagueID string) (*League, error) {
+	return defaultClient.GetLeague(leagueID)
+}
+
+// GetLeagueContext gets the league information
+func GetLeagueContext(ctx context.Context, leagueID string) (*League, error) {
+	return defaultClient.GetLeagueContext(ctx, leagueID)
+}
+
+// GetLeague gets the league information
+//
+// GetLeague uses context.Background internally; to specify the context, use
+// GetLeagueContext.
+func (c *Client) GetLeague(leagueID string) (*League, error) {
+	return c.GetLeagueContext(context.Background(), leagueID)
+}
+
+// GetLeagueContext gets the league information
+func (c *Client) GetLeagueContext(ctx context.Context, leagueID string) (*League, error) {
+	ctx, span := c.startSpan(ctx, "GetLeague", "/leagues/{leagueId}", attribute.String("coc.league_id", leagueID))
+	defer span.End()
+
 	var sb strings.Builder
 	sb.Grow(100)
-	sb.WriteString(config.Data.BaseURL)
+	sb.WriteString(c.baseURL)
 	sb.WriteString("/leagues/")
-	sb.WriteString(fmtTag(leagueID))
+	sb.WriteString(leagueID)
 	url := sb.String()
-	log.Trace(url)
 
-	body, err := get(url, nil)
+	body, err := c.get(ctx, url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -87,7 +130,7 @@ func GetLeague(leagueID string) (*League, error) {
 	var resp respType
 	err = json.Unmarshal(body, &resp)
 	if err != nil {
-		log.Debug("failed to parse the json response")
+		c.log.Debug("failed to parse the json response", "error", err)
 		return nil, err
 	}
 
@@ -95,101 +138,266 @@ func GetLeague(leagueID string) (*League, error) {
 }
 
 // GetLeagues lists the leagues
-func GetLeagues(qparms rest.QParms) ([]League, error) {
+func GetLeagues(opts ...RequestOption) ([]League, error) {
+	return defaultClient.GetLeagues(opts...)
+}
+
+// GetLeaguesContext lists the leagues
+func GetLeaguesContext(ctx context.Context, opts ...RequestOption) ([]League, error) {
+	return defaultClient.GetLeaguesContext(ctx, opts...)
+}
+
+// GetLeaguesPage returns a single page of leagues, along with the cursors of the
+// pages before and after it
+func GetLeaguesPage(ctx context.Context, opts ...RequestOption) (*Page[League], error) {
+	return defaultClient.GetLeaguesPage(ctx, opts...)
+}
+
+// IterateLeagues returns an iterator over the leagues, fetching pages as they are
+// needed. At most maxItems items are returned; if maxItems is zero, every item is
+// returned.
+func IterateLeagues(ctx context.Context, maxItems int, opts ...RequestOption) *Iterator[League] {
+	return defaultClient.IterateLeagues(ctx, maxItems, opts...)
+}
+
+// GetLeagues lists the leagues
+//
+// GetLeagues uses context.Background internally; to specify the context, use
+// GetLeaguesContext.
+func (c *Client) GetLeagues(opts ...RequestOption) ([]League, error) {
+	return c.GetLeaguesContext(context.Background(), opts...)
+}
+
+// GetLeaguesContext lists the leagues
+func (c *Client) GetLeaguesContext(ctx context.Context, opts ...RequestOption) ([]League, error) {
+	page, err := c.GetLeaguesPage(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+// GetLeaguesPage returns a single page of leagues, along with the cursors of the
+// pages before and after it
+func (c *Client) GetLeaguesPage(ctx context.Context, opts ...RequestOption) (*Page[League], error) {
+	ctx, span := c.startSpan(ctx, "GetLeagues", "/leagues")
+	defer span.End()
+
 	var sb strings.Builder
 	sb.Grow(100)
-	sb.WriteString(config.Data.BaseURL)
+	sb.WriteString(c.baseURL)
 	sb.WriteString("/leagues/")
 
-	body, err := get(sb.String(), nil)
+	body, err := c.get(ctx, sb.String(), requestParms(opts))
 	if err != nil {
 		return nil, err
 	}
 
-	// Parse into an array of clans
-	type respType struct {
-		Leagues []League `json:"items"`
-	}
-	var resp respType
-	err = json.Unmarshal(body, &resp)
-	if err != nil {
-		log.Debug("failed to parse the json response")
+	// Parse into a page of leagues
+	var page Page[League]
+	if err := json.Unmarshal(body, &page); err != nil {
+		c.log.Debug("failed to parse the json response", "error", err)
 		return nil, err
 	}
 
-	return resp.Leagues, nil
+	return &page, nil
+}
+
+// IterateLeagues returns an iterator over the leagues, fetching pages as they are
+// needed. At most maxItems items are returned; if maxItems is zero, every item is
+// returned.
+func (c *Client) IterateLeagues(ctx context.Context, maxItems int, opts ...RequestOption) *Iterator[League] {
+	return newIterator(ctx, maxItems, opts, func(ctx context.Context, opts ...RequestOption) (*Page[League], error) {
+		return c.GetLeaguesPage(ctx, opts...)
+	})
+}
+
+// GetLeagueSeasons gets the league seasons
+func GetLeagueSeasons(leagueID string, opts ...RequestOption) ([]LeagueSeason, error) {
+	return defaultClient.GetLeagueSeasons(leagueID, opts...)
+}
+
+// GetLeagueSeasonsContext gets the league seasons
+func GetLeagueSeasonsContext(ctx context.Context, leagueID string, opts ...RequestOption) ([]LeagueSeason, error) {
+	return defaultClient.GetLeagueSeasonsContext(ctx, leagueID, opts...)
+}
+
+// GetLeagueSeasonsPage returns a single page of league seasons, along with the
+// cursors of the pages before and after it
+func GetLeagueSeasonsPage(ctx context.Context, leagueID string, opts ...RequestOption) (*Page[LeagueSeason], error) {
+	return defaultClient.GetLeagueSeasonsPage(ctx, leagueID, opts...)
+}
+
+// IterateLeagueSeasons returns an iterator over the league seasons, fetching pages
+// as they are needed. At most maxItems items are returned; if maxItems is zero,
+// every item is returned.
+func IterateLeagueSeasons(ctx context.Context, leagueID string, maxItems int, opts ...RequestOption) *Iterator[LeagueSeason] {
+	return defaultClient.IterateLeagueSeasons(ctx, leagueID, maxItems, opts...)
 }
 
 // GetLeagueSeasons gets the league seasons
-func GetLeagueSeasons(leagueID string) ([]LeagueSeason, error) {
+//
+// GetLeagueSeasons uses context.Background internally; to specify the context, use
+// GetLeagueSeasonsContext.
+func (c *Client) GetLeagueSeasons(leagueID string, opts ...RequestOption) ([]LeagueSeason, error) {
+	return c.GetLeagueSeasonsContext(context.Background(), leagueID, opts...)
+}
+
+// GetLeagueSeasonsContext gets the league seasons
+func (c *Client) GetLeagueSeasonsContext(ctx context.Context, leagueID string, opts ...RequestOption) ([]LeagueSeason, error) {
+	page, err := c.GetLeagueSeasonsPage(ctx, leagueID, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+// GetLeagueSeasonsPage returns a single page of league seasons, along with the
+// cursors of the pages before and after it
+func (c *Client) GetLeagueSeasonsPage(ctx context.Context, leagueID string, opts ...RequestOption) (*Page[LeagueSeason], error) {
+	ctx, span := c.startSpan(ctx, "GetLeagueSeasons", "/leagues/{leagueId}/seasons", attribute.String("coc.league_id", leagueID))
+	defer span.End()
+
 	var sb strings.Builder
 	sb.Grow(100)
-	sb.WriteString(config.Data.BaseURL)
+	sb.WriteString(c.baseURL)
 	sb.WriteString("/leagues/")
-	sb.WriteString(fmtTag(leagueID))
+	sb.WriteString(leagueID)
 	sb.WriteString("/seasons")
 	url := sb.String()
-	log.Trace(url)
 
-	body, err := get(url, nil)
+	body, err := c.get(ctx, url, requestParms(opts))
 	if err != nil {
 		return nil, err
 	}
 
-	// Parse into an array of clans
-	type respType struct {
-		Seasons []LeagueSeason `json:"items"`
-	}
-	var resp respType
-	err = json.Unmarshal(body, &resp)
-	if err != nil {
-		log.Debug("failed to parse the json response")
+	// Parse into a page of league seasons
+	var page Page[LeagueSeason]
+	if err := json.Unmarshal(body, &page); err != nil {
+		c.log.Debug("failed to parse the json response", "error", err)
 		return nil, err
 	}
 
-	return resp.Seasons, nil
+	return &page, nil
+}
+
+// IterateLeagueSeasons returns an iterator over the league seasons, fetching pages
+// as they are needed. At most maxItems items are returned; if maxItems is zero,
+// every item is returned.
+func (c *Client) IterateLeagueSeasons(ctx context.Context, leagueID string, maxItems int, opts ...RequestOption) *Iterator[LeagueSeason] {
+	return newIterator(ctx, maxItems, opts, func(ctx context.Context, opts ...RequestOption) (*Page[LeagueSeason], error) {
+		return c.GetLeagueSeasonsPage(ctx, leagueID, opts...)
+	})
 }
 
 // GetLeagueSeasonRankings gets the league season rankings for Legend League
-func GetLeagueSeasonRankings(leagueID string) ([]LeagueSeasonRanking, error) {
+func GetLeagueSeasonRankings(leagueID string, opts ...RequestOption) ([]LeagueSeasonRanking, error) {
+	return defaultClient.GetLeagueSeasonRankings(leagueID, opts...)
+}
+
+// GetLeagueSeasonRankingsContext gets the league season rankings for Legend League
+func GetLeagueSeasonRankingsContext(ctx context.Context, leagueID string, opts ...RequestOption) ([]LeagueSeasonRanking, error) {
+	return defaultClient.GetLeagueSeasonRankingsContext(ctx, leagueID, opts...)
+}
+
+// GetLeagueSeasonRankingsPage returns a single page of league season rankings, along
+// with the cursors of the pages before and after it
+func GetLeagueSeasonRankingsPage(ctx context.Context, leagueID string, opts ...RequestOption) (*Page[LeagueSeasonRanking], error) {
+	return defaultClient.GetLeagueSeasonRankingsPage(ctx, leagueID, opts...)
+}
+
+// IterateLeagueSeasonRankings returns an iterator over the league season rankings,
+// fetching pages as they are needed. At most maxItems items are returned; if
+// maxItems is zero, every item is returned.
+func IterateLeagueSeasonRankings(ctx context.Context, leagueID string, maxItems int, opts ...RequestOption) *Iterator[LeagueSeasonRanking] {
+	return defaultClient.IterateLeagueSeasonRankings(ctx, leagueID, maxItems, opts...)
+}
+
+// GetLeagueSeasonRankings gets the league season rankings for Legend League
+//
+// GetLeagueSeasonRankings uses context.Background internally; to specify the
+// context, use GetLeagueSeasonRankingsContext.
+func (c *Client) GetLeagueSeasonRankings(leagueID string, opts ...RequestOption) ([]LeagueSeasonRanking, error) {
+	return c.GetLeagueSeasonRankingsContext(context.Background(), leagueID, opts...)
+}
+
+// GetLeagueSeasonRankingsContext gets the league season rankings for Legend League
+func (c *Client) GetLeagueSeasonRankingsContext(ctx context.Context, leagueID string, opts ...RequestOption) ([]LeagueSeasonRanking, error) {
+	page, err := c.GetLeagueSeasonRankingsPage(ctx, leagueID, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+// GetLeagueSeasonRankingsPage returns a single page of league season rankings, along
+// with the cursors of the pages before and after it
+func (c *Client) GetLeagueSeasonRankingsPage(ctx context.Context, leagueID string, opts ...RequestOption) (*Page[LeagueSeasonRanking], error) {
+	ctx, span := c.startSpan(ctx, "GetLeagueSeasonRankings", "/leagues/{leagueId}", attribute.String("coc.league_id", leagueID))
+	defer span.End()
+
 	var sb strings.Builder
 	sb.Grow(100)
-	sb.WriteString(config.Data.BaseURL)
+	sb.WriteString(c.baseURL)
 	sb.WriteString("/leagues/")
-	sb.WriteString(fmtTag(leagueID))
+	sb.WriteString(leagueID)
 	url := sb.String()
-	log.Trace(url)
 
-	body, err := get(url, nil)
+	body, err := c.get(ctx, url, requestParms(opts))
 	if err != nil {
 		return nil, err
 	}
 
-	// Parse into an array of clans
-	type respType struct {
-		Rankings []LeagueSeasonRanking `json:"items"`
-	}
-	var resp respType
-	err = json.Unmarshal(body, &resp)
-	if err != nil {
-		log.Debug("failed to parse the json response")
+	// Parse into a page of league season rankings
+	var page Page[LeagueSeasonRanking]
+	if err := json.Unmarshal(body, &page); err != nil {
+		c.log.Debug("failed to parse the json response", "error", err)
 		return nil, err
 	}
 
-	return resp.Rankings, nil
+	return &page, nil
+}
+
+// IterateLeagueSeasonRankings returns an iterator over the league season rankings,
+// fetching pages as they are needed. At most maxItems items are returned; if
+// maxItems is zero, every item is returned.
+func (c *Client) IterateLeagueSeasonRankings(ctx context.Context, leagueID string, maxItems int, opts ...RequestOption) *Iterator[LeagueSeasonRanking] {
+	return newIterator(ctx, maxItems, opts, func(ctx context.Context, opts ...RequestOption) (*Page[LeagueSeasonRanking], error) {
+		return c.GetLeagueSeasonRankingsPage(ctx, leagueID, opts...)
+	})
 }
 
-// GetLWareague gets the war league information
+// GetWarLeague gets the war league information
 func GetWarLeague(leagueID string) (*WarLeague, error) {
+	return defaultClient.GetWarLeague(leagueID)
+}
+
+// GetWarLeagueContext gets the war league information
+func GetWarLeagueContext(ctx context.Context, leagueID string) (*WarLeague, error) {
+	return defaultClient.GetWarLeagueContext(ctx, leagueID)
+}
+
+// GetWarLeague gets the war league information
+//
+// GetWarLeague uses context.Background internally; to specify the context, use
+// GetWarLeagueContext.
+func (c *Client) GetWarLeague(leagueID string) (*WarLeague, error) {
+	return c.GetWarLeagueContext(context.Background(), leagueID)
+}
+
+// GetWarLeagueContext gets the war league information
+func (c *Client) GetWarLeagueContext(ctx context.Context, leagueID string) (*WarLeague, error) {
+	ctx, span := c.startSpan(ctx, "GetWarLeague", "/warleagues/{leagueId}", attribute.String("coc.league_id", leagueID))
+	defer span.End()
+
 	var sb strings.Builder
 	sb.Grow(100)
-	sb.WriteString(config.Data.BaseURL)
+	sb.WriteString(c.baseURL)
 	sb.WriteString("/warleagues/")
-	sb.WriteString(fmtTag(leagueID))
+	sb.WriteString(leagueID)
 	url := sb.String()
-	log.Trace(url)
 
-	body, err := get(url, nil)
+	body, err := c.get(ctx, url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -201,7 +409,7 @@ func GetWarLeague(leagueID string) (*WarLeague, error) {
 	var resp respType
 	err = json.Unmarshal(body, &resp)
 	if err != nil {
-		log.Debug("failed to parse the json response")
+		c.log.Debug("failed to parse the json response", "error", err)
 		return nil, err
 	}
 
@@ -209,29 +417,319 @@ func GetWarLeague(leagueID string) (*WarLeague, error) {
 }
 
 // GetWarLeagues lists the war leagues
-func GetWarLeagues(qparms rest.QParms) ([]WarLeague, error) {
+func GetWarLeagues(opts ...RequestOption) ([]WarLeague, error) {
+	return defaultClient.GetWarLeagues(opts...)
+}
+
+// GetWarLeaguesContext lists the war leagues
+func GetWarLeaguesContext(ctx context.Context, opts ...RequestOption) ([]WarLeague, error) {
+	return defaultClient.GetWarLeaguesContext(ctx, opts...)
+}
+
+// GetWarLeaguesPage returns a single page of war leagues, along with the cursors of
+// the pages before and after it
+func GetWarLeaguesPage(ctx context.Context, opts ...RequestOption) (*Page[WarLeague], error) {
+	return defaultClient.GetWarLeaguesPage(ctx, opts...)
+}
+
+// IterateWarLeagues returns an iterator over the war leagues, fetching pages as they
+// are needed. At most maxItems items are returned; if maxItems is zero, every item
+// is returned.
+func IterateWarLeagues(ctx context.Context, maxItems int, opts ...RequestOption) *Iterator[WarLeague] {
+	return defaultClient.IterateWarLeagues(ctx, maxItems, opts...)
+}
+
+// GetWarLeagues lists the war leagues
+//
+// GetWarLeagues uses context.Background internally; to specify the context, use
+// GetWarLeaguesContext.
+func (c *Client) GetWarLeagues(opts ...RequestOption) ([]WarLeague, error) {
+	return c.GetWarLeaguesContext(context.Background(), opts...)
+}
+
+// GetWarLeaguesContext lists the war leagues
+func (c *Client) GetWarLeaguesContext(ctx context.Context, opts ...RequestOption) ([]WarLeague, error) {
+	page, err := c.GetWarLeaguesPage(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+// GetWarLeaguesPage returns a single page of war leagues, along with the cursors of
+// the pages before and after it
+func (c *Client) GetWarLeaguesPage(ctx context.Context, opts ...RequestOption) (*Page[WarLeague], error) {
+	ctx, span := c.startSpan(ctx, "GetWarLeagues", "/warleagues")
+	defer span.End()
+
 	var sb strings.Builder
 	sb.Grow(100)
-	sb.WriteString(config.Data.BaseURL)
+	sb.WriteString(c.baseURL)
 	sb.WriteString("/warleagues/")
 	url := sb.String()
-	log.Trace(url)
 
-	body, err := get(url, nil)
+	body, err := c.get(ctx, url, requestParms(opts))
 	if err != nil {
 		return nil, err
 	}
 
-	// Parse into an array of clans
-	type respType struct {
-		Leagues []WarLeague `json:"items"`
+	// Parse into a page of war leagues
+	var page Page[WarLeague]
+	if err := json.Unmarshal(body, &page); err != nil {
+		c.log.Debug("failed to parse the json response", "error", err)
+		return nil, err
 	}
-	var resp respType
-	err = json.Unmarshal(body, &resp)
+
+	return &page, nil
+}
+
+// IterateWarLeagues returns an iterator over the war leagues, fetching pages as they
+// are needed. At most maxItems items are returned; if maxItems is zero, every item
+// is returned.
+func (c *Client) IterateWarLeagues(ctx context.Context, maxItems int, opts ...RequestOption) *Iterator[WarLeague] {
+	return newIterator(ctx, maxItems, opts, func(ctx context.Context, opts ...RequestOption) (*Page[WarLeague], error) {
+		return c.GetWarLeaguesPage(ctx, opts...)
+	})
+}
+
+// GetBuilderBaseLeague gets the builder base league information
+func GetBuilderBaseLeague(leagueID string) (*BuilderBaseLeague, error) {
+	return defaultClient.GetBuilderBaseLeague(leagueID)
+}
+
+// GetBuilderBaseLeagueContext gets the builder base league information
+func GetBuilderBaseLeagueContext(ctx context.Context, leagueID string) (*BuilderBaseLeague, error) {
+	return defaultClient.GetBuilderBaseLeagueContext(ctx, leagueID)
+}
+
+// GetBuilderBaseLeague gets the builder base league information
+//
+// GetBuilderBaseLeague uses context.Background internally; to specify the context, use
+// GetBuilderBaseLeagueContext.
+func (c *Client) GetBuilderBaseLeague(leagueID string) (*BuilderBaseLeague, error) {
+	return c.GetBuilderBaseLeagueContext(context.Background(), leagueID)
+}
+
+// GetBuilderBaseLeagueContext gets the builder base league information
+func (c *Client) GetBuilderBaseLeagueContext(ctx context.Context, leagueID string) (*BuilderBaseLeague, error) {
+	ctx, span := c.startSpan(ctx, "GetBuilderBaseLeague", "/builderbaseleagues/{leagueId}", attribute.String("coc.league_id", leagueID))
+	defer span.End()
+
+	var sb strings.Builder
+	sb.Grow(100)
+	sb.WriteString(c.baseURL)
+	sb.WriteString("/builderbaseleagues/")
+	sb.WriteString(leagueID)
+	url := sb.String()
+
+	body, err := c.get(ctx, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse into a builder base league
+	var league BuilderBaseLeague
+	if err := json.Unmarshal(body, &league); err != nil {
+		c.log.Debug("failed to parse the json response", "error", err)
+		return nil, err
+	}
+
+	return &league, nil
+}
+
+// GetBuilderBaseLeagues lists the builder base leagues
+func GetBuilderBaseLeagues(opts ...RequestOption) ([]BuilderBaseLeague, error) {
+	return defaultClient.GetBuilderBaseLeagues(opts...)
+}
+
+// GetBuilderBaseLeaguesContext lists the builder base leagues
+func GetBuilderBaseLeaguesContext(ctx context.Context, opts ...RequestOption) ([]BuilderBaseLeague, error) {
+	return defaultClient.GetBuilderBaseLeaguesContext(ctx, opts...)
+}
+
+// GetBuilderBaseLeaguesPage returns a single page of builder base leagues, along
+// with the cursors of the pages before and after it
+func GetBuilderBaseLeaguesPage(ctx context.Context, opts ...RequestOption) (*Page[BuilderBaseLeague], error) {
+	return defaultClient.GetBuilderBaseLeaguesPage(ctx, opts...)
+}
+
+// IterateBuilderBaseLeagues returns an iterator over the builder base leagues,
+// fetching pages as they are needed. At most maxItems items are returned; if
+// maxItems is zero, every item is returned.
+func IterateBuilderBaseLeagues(ctx context.Context, maxItems int, opts ...RequestOption) *Iterator[BuilderBaseLeague] {
+	return defaultClient.IterateBuilderBaseLeagues(ctx, maxItems, opts...)
+}
+
+// GetBuilderBaseLeagues lists the builder base leagues
+//
+// GetBuilderBaseLeagues uses context.Background internally; to specify the context, use
+// GetBuilderBaseLeaguesContext.
+func (c *Client) GetBuilderBaseLeagues(opts ...RequestOption) ([]BuilderBaseLeague, error) {
+	return c.GetBuilderBaseLeaguesContext(context.Background(), opts...)
+}
+
+// GetBuilderBaseLeaguesContext lists the builder base leagues
+func (c *Client) GetBuilderBaseLeaguesContext(ctx context.Context, opts ...RequestOption) ([]BuilderBaseLeague, error) {
+	page, err := c.GetBuilderBaseLeaguesPage(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+// GetBuilderBaseLeaguesPage returns a single page of builder base leagues, along
+// with the cursors of the pages before and after it
+func (c *Client) GetBuilderBaseLeaguesPage(ctx context.Context, opts ...RequestOption) (*Page[BuilderBaseLeague], error) {
+	ctx, span := c.startSpan(ctx, "GetBuilderBaseLeagues", "/builderbaseleagues")
+	defer span.End()
+
+	var sb strings.Builder
+	sb.Grow(100)
+	sb.WriteString(c.baseURL)
+	sb.WriteString("/builderbaseleagues")
+	url := sb.String()
+
+	body, err := c.get(ctx, url, requestParms(opts))
 	if err != nil {
-		log.Debug("failed to parse the json response")
 		return nil, err
 	}
 
-	return resp.Leagues, nil
+	// Parse into a page of builder base leagues
+	var page Page[BuilderBaseLeague]
+	if err := json.Unmarshal(body, &page); err != nil {
+		c.log.Debug("failed to parse the json response", "error", err)
+		return nil, err
+	}
+
+	return &page, nil
+}
+
+// IterateBuilderBaseLeagues returns an iterator over the builder base leagues,
+// fetching pages as they are needed. At most maxItems items are returned; if
+// maxItems is zero, every item is returned.
+func (c *Client) IterateBuilderBaseLeagues(ctx context.Context, maxItems int, opts ...RequestOption) *Iterator[BuilderBaseLeague] {
+	return newIterator(ctx, maxItems, opts, func(ctx context.Context, opts ...RequestOption) (*Page[BuilderBaseLeague], error) {
+		return c.GetBuilderBaseLeaguesPage(ctx, opts...)
+	})
+}
+
+// GetCapitalLeague gets the capital league information
+func GetCapitalLeague(leagueID string) (*CapitalLeague, error) {
+	return defaultClient.GetCapitalLeague(leagueID)
+}
+
+// GetCapitalLeagueContext gets the capital league information
+func GetCapitalLeagueContext(ctx context.Context, leagueID string) (*CapitalLeague, error) {
+	return defaultClient.GetCapitalLeagueContext(ctx, leagueID)
+}
+
+// GetCapitalLeague gets the capital league information
+//
+// GetCapitalLeague uses context.Background internally; to specify the context, use
+// GetCapitalLeagueContext.
+func (c *Client) GetCapitalLeague(leagueID string) (*CapitalLeague, error) {
+	return c.GetCapitalLeagueContext(context.Background(), leagueID)
+}
+
+// GetCapitalLeagueContext gets the capital league information
+func (c *Client) GetCapitalLeagueContext(ctx context.Context, leagueID string) (*CapitalLeague, error) {
+	ctx, span := c.startSpan(ctx, "GetCapitalLeague", "/capitalleagues/{leagueId}", attribute.String("coc.league_id", leagueID))
+	defer span.End()
+
+	var sb strings.Builder
+	sb.Grow(100)
+	sb.WriteString(c.baseURL)
+	sb.WriteString("/capitalleagues/")
+	sb.WriteString(leagueID)
+	url := sb.String()
+
+	body, err := c.get(ctx, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse into a capital league
+	var league CapitalLeague
+	if err := json.Unmarshal(body, &league); err != nil {
+		c.log.Debug("failed to parse the json response", "error", err)
+		return nil, err
+	}
+
+	return &league, nil
+}
+
+// GetCapitalLeagues lists the capital leagues
+func GetCapitalLeagues(opts ...RequestOption) ([]CapitalLeague, error) {
+	return defaultClient.GetCapitalLeagues(opts...)
+}
+
+// GetCapitalLeaguesContext lists the capital leagues
+func GetCapitalLeaguesContext(ctx context.Context, opts ...RequestOption) ([]CapitalLeague, error) {
+	return defaultClient.GetCapitalLeaguesContext(ctx, opts...)
+}
+
+// GetCapitalLeaguesPage returns a single page of capital leagues, along with the
+// cursors of the pages before and after it
+func GetCapitalLeaguesPage(ctx context.Context, opts ...RequestOption) (*Page[CapitalLeague], error) {
+	return defaultClient.GetCapitalLeaguesPage(ctx, opts...)
+}
+
+// IterateCapitalLeagues returns an iterator over the capital leagues, fetching pages
+// as they are needed. At most maxItems items are returned; if maxItems is zero,
+// every item is returned.
+func IterateCapitalLeagues(ctx context.Context, maxItems int, opts ...RequestOption) *Iterator[CapitalLeague] {
+	return defaultClient.IterateCapitalLeagues(ctx, maxItems, opts...)
+}
+
+// GetCapitalLeagues lists the capital leagues
+//
+// GetCapitalLeagues uses context.Background internally; to specify the context, use
+// GetCapitalLeaguesContext.
+func (c *Client) GetCapitalLeagues(opts ...RequestOption) ([]CapitalLeague, error) {
+	return c.GetCapitalLeaguesContext(context.Background(), opts...)
+}
+
+// GetCapitalLeaguesContext lists the capital leagues
+func (c *Client) GetCapitalLeaguesContext(ctx context.Context, opts ...RequestOption) ([]CapitalLeague, error) {
+	page, err := c.GetCapitalLeaguesPage(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+// GetCapitalLeaguesPage returns a single page of capital leagues, along with the
+// cursors of the pages before and after it
+func (c *Client) GetCapitalLeaguesPage(ctx context.Context, opts ...RequestOption) (*Page[CapitalLeague], error) {
+	ctx, span := c.startSpan(ctx, "GetCapitalLeagues", "/capitalleagues")
+	defer span.End()
+
+	var sb strings.Builder
+	sb.Grow(100)
+	sb.WriteString(c.baseURL)
+	sb.WriteString("/capitalleagues")
+	url := sb.String()
+
+	body, err := c.get(ctx, url, requestParms(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse into a page of capital leagues
+	var page Page[CapitalLeague]
+	if err := json.Unmarshal(body, &page); err != nil {
+		c.log.Debug("failed to parse the json response", "error", err)
+		return nil, err
+	}
+
+	return &page, nil
+}
+
+// IterateCapitalLeagues returns an iterator over the capital leagues, fetching pages
+// as they are needed. At most maxItems items are returned; if maxItems is zero,
+// every item is returned.
+func (c *Client) IterateCapitalLeagues(ctx context.Context, maxItems int, opts ...RequestOption) *Iterator[CapitalLeague] {
+	return newIterator(ctx, maxItems, opts, func(ctx context.Context, opts ...RequestOption) (*Page[CapitalLeague], error) {
+		return c.GetCapitalLeaguesPage(ctx, opts...)
+	})
 }
@@ -0,0 +1,75 @@
+package coc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNumericIDPaths(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		get  func(c *Client) error
+		want string
+	}{
+		{
+			name: "league",
+			get:  func(c *Client) error { _, err := c.GetLeagueContext(ctx, "29000022"); return err },
+			want: "/leagues/29000022",
+		},
+		{
+			name: "league seasons",
+			get:  func(c *Client) error { _, err := c.GetLeagueSeasonsPage(ctx, "29000022"); return err },
+			want: "/leagues/29000022/seasons",
+		},
+		{
+			name: "league season rankings",
+			get:  func(c *Client) error { _, err := c.GetLeagueSeasonRankingsPage(ctx, "29000022"); return err },
+			want: "/leagues/29000022",
+		},
+		{
+			name: "war league",
+			get:  func(c *Client) error { _, err := c.GetWarLeagueContext(ctx, "48000018"); return err },
+			want: "/warleagues/48000018",
+		},
+		{
+			name: "builder base league",
+			get:  func(c *Client) error { _, err := c.GetBuilderBaseLeagueContext(ctx, "44000041"); return err },
+			want: "/builderbaseleagues/44000041",
+		},
+		{
+			name: "capital league",
+			get:  func(c *Client) error { _, err := c.GetCapitalLeagueContext(ctx, "85000022"); return err },
+			want: "/capitalleagues/85000022",
+		},
+		{
+			name: "location",
+			get:  func(c *Client) error { _, err := c.GetLocationContext(ctx, "32000006"); return err },
+			want: "/locations/32000006",
+		},
+		{
+			name: "clan rankings",
+			get:  func(c *Client) error { _, err := c.GetClanRankingsPage(ctx, "32000006"); return err },
+			want: "/locations/32000006/rankings/clans",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.URL.EscapedPath()
+				w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+
+			if err := tt.get(NewClient("token", WithBaseURL(server.URL))); err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got path %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,12 +1,11 @@
 package coc
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 
-	"github.com/clashgolang/coc/pkg/config"
-	"github.com/clashgolang/coc/pkg/rest"
-	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Location is information about a location
@@ -26,15 +25,35 @@ func (l Location) String() string {
 
 // GetLocation gets the location information
 func GetLocation(id string) (*Location, error) {
+	return defaultClient.GetLocation(id)
+}
+
+// GetLocationContext gets the location information
+func GetLocationContext(ctx context.Context, id string) (*Location, error) {
+	return defaultClient.GetLocationContext(ctx, id)
+}
+
+// GetLocation gets the location information
+//
+// GetLocation uses context.Background internally; to specify the context, use
+// GetLocationContext.
+func (c *Client) GetLocation(id string) (*Location, error) {
+	return c.GetLocationContext(context.Background(), id)
+}
+
+// GetLocationContext gets the location information
+func (c *Client) GetLocationContext(ctx context.Context, id string) (*Location, error) {
+	ctx, span := c.startSpan(ctx, "GetLocation", "/locations/{locationId}", attribute.String("coc.location_id", id))
+	defer span.End()
+
 	var sb strings.Builder
 	sb.Grow(100)
-	sb.WriteString(config.Data.BaseURL)
+	sb.WriteString(c.baseURL)
 	sb.WriteString("/locations/")
-	sb.WriteString(fmtTag(id))
+	sb.WriteString(id)
 	url := sb.String()
-	log.Trace(url)
 
-	body, err := get(url, nil)
+	body, err := c.get(ctx, url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -46,7 +65,7 @@ func GetLocation(id string) (*Location, error) {
 	var resp respType
 	err = json.Unmarshal(body, &resp)
 	if err != nil {
-		log.Debug("failed to parse the json response")
+		c.log.Debug("failed to parse the json response", "error", err)
 		return nil, err
 	}
 
@@ -55,27 +74,76 @@ func GetLocation(id string) (*Location, error) {
 }
 
 // GetLocations lists locations
-func GetLocations(qparms rest.QParms) ([]Location, error) {
+func GetLocations(opts ...RequestOption) ([]Location, error) {
+	return defaultClient.GetLocations(opts...)
+}
+
+// GetLocationsContext lists locations
+func GetLocationsContext(ctx context.Context, opts ...RequestOption) ([]Location, error) {
+	return defaultClient.GetLocationsContext(ctx, opts...)
+}
+
+// GetLocationsPage returns a single page of locations, along with the cursors of the
+// pages before and after it
+func GetLocationsPage(ctx context.Context, opts ...RequestOption) (*Page[Location], error) {
+	return defaultClient.GetLocationsPage(ctx, opts...)
+}
+
+// IterateLocations returns an iterator over the locations, fetching pages as they
+// are needed. At most maxItems items are returned; if maxItems is zero, every item
+// is returned.
+func IterateLocations(ctx context.Context, maxItems int, opts ...RequestOption) *Iterator[Location] {
+	return defaultClient.IterateLocations(ctx, maxItems, opts...)
+}
+
+// GetLocations lists locations
+//
+// GetLocations uses context.Background internally; to specify the context, use
+// GetLocationsContext.
+func (c *Client) GetLocations(opts ...RequestOption) ([]Location, error) {
+	return c.GetLocationsContext(context.Background(), opts...)
+}
+
+// GetLocationsContext lists locations
+func (c *Client) GetLocationsContext(ctx context.Context, opts ...RequestOption) ([]Location, error) {
+	page, err := c.GetLocationsPage(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+// GetLocationsPage returns a single page of locations, along with the cursors of the
+// pages before and after it
+func (c *Client) GetLocationsPage(ctx context.Context, opts ...RequestOption) (*Page[Location], error) {
+	ctx, span := c.startSpan(ctx, "GetLocations", "/locations")
+	defer span.End()
+
 	var sb strings.Builder
 	sb.Grow(100)
-	sb.WriteString(config.Data.BaseURL)
+	sb.WriteString(c.baseURL)
 	sb.WriteString("/locations")
 
-	body, err := get(sb.String(), nil)
+	body, err := c.get(ctx, sb.String(), requestParms(opts))
 	if err != nil {
 		return nil, err
 	}
 
-	// Parse into an array of clans
-	type respType struct {
-		Locations []Location `json:"items"`
-	}
-	var resp respType
-	err = json.Unmarshal(body, &resp)
-	if err != nil {
-		log.Debug("failed to parse the json response")
+	// Parse into a page of locations
+	var page Page[Location]
+	if err := json.Unmarshal(body, &page); err != nil {
+		c.log.Debug("failed to parse the json response", "error", err)
 		return nil, err
 	}
 
-	return resp.Locations, nil
+	return &page, nil
+}
+
+// IterateLocations returns an iterator over the locations, fetching pages as they
+// are needed. At most maxItems items are returned; if maxItems is zero, every item
+// is returned.
+func (c *Client) IterateLocations(ctx context.Context, maxItems int, opts ...RequestOption) *Iterator[Location] {
+	return newIterator(ctx, maxItems, opts, func(ctx context.Context, opts ...RequestOption) (*Page[Location], error) {
+		return c.GetLocationsPage(ctx, opts...)
+	})
 }
@@ -0,0 +1,65 @@
+package coc
+
+import (
+	"github.com/clashgolang/coc/pkg/rest"
+)
+
+// RequestOption sets an optional query parameter on a request to a list endpoint
+type RequestOption func(*requestOptions)
+
+// requestOptions are the optional query parameters of a request to a list endpoint
+type requestOptions struct {
+	limit  int
+	after  string
+	before string
+}
+
+// WithLimit limits the number of items returned in a page
+func WithLimit(limit int) RequestOption {
+	return func(o *requestOptions) {
+		o.limit = limit
+	}
+}
+
+// WithAfter returns the page after the one with the given cursor. The API does not
+// accept both cursors, so it replaces any cursor set by an earlier WithBefore.
+func WithAfter(cursor string) RequestOption {
+	return func(o *requestOptions) {
+		o.after = cursor
+		o.before = ""
+	}
+}
+
+// WithBefore returns the page before the one with the given cursor. The API does not
+// accept both cursors, so it replaces any cursor set by an earlier WithAfter.
+func WithBefore(cursor string) RequestOption {
+	return func(o *requestOptions) {
+		o.before = cursor
+		o.after = ""
+	}
+}
+
+// requestParms applies the options and returns the resulting query parameters
+func requestParms(opts []RequestOption) rest.QParms {
+	if len(opts) == 0 {
+		return nil
+	}
+	var o requestOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+
+	qparms := rest.QParms{}
+	if o.limit > 0 {
+		qparms["limit"] = o.limit
+	}
+	if o.after != "" {
+		qparms["after"] = o.after
+	}
+	if o.before != "" {
+		qparms["before"] = o.before
+	}
+	return qparms
+}
@@ -0,0 +1,135 @@
+package coc
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Cursors are the markers used to fetch the pages before and after a page of results
+type Cursors struct {
+	After  string `json:"after,omitempty"`
+	Before string `json:"before,omitempty"`
+}
+
+// Paging is the paging information returned with a page of results
+type Paging struct {
+	Cursors Cursors `json:"cursors"`
+}
+
+// Page is a single page of results returned by a list endpoint
+type Page[T any] struct {
+	Items  []T    `json:"items"`
+	Paging Paging `json:"paging"`
+}
+
+// String returns a string representation of a page
+func (p Page[T]) String() string {
+	b, _ := json.Marshal(p)
+	return string(b)
+}
+
+// HasNext returns whether there is a page after this one
+func (p Page[T]) HasNext() bool {
+	return p.Paging.Cursors.After != ""
+}
+
+// HasPrevious returns whether there is a page before this one
+func (p Page[T]) HasPrevious() bool {
+	return p.Paging.Cursors.Before != ""
+}
+
+// pageFunc fetches a single page of results using the request options
+type pageFunc[T any] func(ctx context.Context, opts ...RequestOption) (*Page[T], error)
+
+// Iterator walks every item returned by a list endpoint, fetching the next page when
+// the items in the current one have been used. An iterator is not safe for concurrent
+// use.
+//
+//	it := client.IterateClanMembers(ctx, tag, 0)
+//	for it.Next() {
+//		member := it.Value()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	ctx      context.Context
+	fetch    pageFunc[T]
+	opts     []RequestOption
+	maxItems int
+	page     *Page[T]
+	index    int
+	count    int
+	value    T
+	err      error
+	done     bool
+}
+
+// newIterator creates an iterator that fetches pages using the function. The request
+// options are used on every request, with the cursor of the next page added; that
+// cursor replaces any WithBefore cursor, which only applies to the first page.
+func newIterator[T any](ctx context.Context, maxItems int, opts []RequestOption, fetch pageFunc[T]) *Iterator[T] {
+	return &Iterator[T]{
+		ctx:      ctx,
+		fetch:    fetch,
+		opts:     opts,
+		maxItems: maxItems,
+	}
+}
+
+// Next advances the iterator to the next item, and returns whether there is one. It
+// returns false when every item has been returned, the maximum number of items has
+// been reached or an error occurs.
+func (it *Iterator[T]) Next() bool {
+	if it.done {
+		return false
+	}
+	if it.maxItems > 0 && it.count >= it.maxItems {
+		it.done = true
+		return false
+	}
+
+	// Fetch pages until one has an item that has not been returned yet
+	for it.page == nil || it.index >= len(it.page.Items) {
+		if it.page != nil && !it.page.HasNext() {
+			it.done = true
+			return false
+		}
+		opts := it.opts[:len(it.opts):len(it.opts)]
+		if it.page != nil {
+			opts = append(opts, WithAfter(it.page.Paging.Cursors.After))
+		}
+		page, err := it.fetch(it.ctx, opts...)
+		if err != nil {
+			it.err = err
+			it.done = true
+			return false
+		}
+		it.page = page
+		it.index = 0
+	}
+
+	it.value = it.page.Items[it.index]
+	it.index++
+	it.count++
+	return true
+}
+
+// Value returns the current item
+func (it *Iterator[T]) Value() T {
+	return it.value
+}
+
+// Err returns the error that stopped the iterator, if any
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Cursors returns the cursors of the most recently fetched page
+func (it *Iterator[T]) Cursors() Cursors {
+	if it.page == nil {
+		return Cursors{}
+	}
+	return it.page.Paging.Cursors
+}
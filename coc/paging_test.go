@@ -0,0 +1,72 @@
+package coc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/clashgolang/coc/pkg/rest"
+)
+
+func TestRequestParms(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []RequestOption
+		want rest.QParms
+	}{
+		{name: "none", opts: nil, want: nil},
+		{name: "nil option", opts: []RequestOption{nil}, want: rest.QParms{}},
+		{name: "limit", opts: []RequestOption{WithLimit(10)}, want: rest.QParms{"limit": 10}},
+		{name: "after", opts: []RequestOption{WithLimit(10), WithAfter("a")}, want: rest.QParms{"limit": 10, "after": "a"}},
+		{name: "before", opts: []RequestOption{WithBefore("b")}, want: rest.QParms{"before": "b"}},
+		{name: "after replaces before", opts: []RequestOption{WithBefore("b"), WithAfter("a")}, want: rest.QParms{"after": "a"}},
+		{name: "before replaces after", opts: []RequestOption{WithAfter("a"), WithBefore("b")}, want: rest.QParms{"before": "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := requestParms(tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIteratorWithBefore(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Has("after") && query.Has("before") {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"reason":"badRequest"}`))
+			return
+		}
+		requests = append(requests, r.URL.RawQuery)
+		after := ""
+		if len(requests) < 3 {
+			after = fmt.Sprintf(`"after":"page%d"`, len(requests)+1)
+		}
+		fmt.Fprintf(w, `{"items":[{"id":%d}],"paging":{"cursors":{%s}}}`, len(requests), after)
+	}))
+	defer server.Close()
+
+	client := NewClient("token", WithBaseURL(server.URL))
+	it := client.IterateLeagues(context.Background(), 0, WithLimit(1), WithBefore("page0"))
+	count := 0
+	for it.Next() {
+		count++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if count != 3 {
+		t.Errorf("got %d leagues, want 3", count)
+	}
+	want := []string{"before=page0&limit=1", "after=page2&limit=1", "after=page3&limit=1"}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("got requests %v, want %v", requests, want)
+	}
+}
@@ -1,12 +1,13 @@
 package coc
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"strings"
 
-	"github.com/clashgolang/coc/pkg/config"
 	"github.com/clashgolang/coc/pkg/rest"
-	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Player is a single player in Clash of Clans.
@@ -111,25 +112,91 @@ func (l PlayerVersusRanking) String() string {
 	return string(b)
 }
 
+// PlayerBuilderBaseRanking is the builder base ranking of a player for a specific location
+type PlayerBuilderBaseRanking struct {
+	Clan                ClanReference     `json:"clan"`
+	BuilderBaseLeague   BuilderBaseLeague `json:"builderBaseLeague"`
+	Tag                 string            `json:"tag"`
+	Name                string            `json:"name"`
+	ExpLevel            int               `json:"expLevel"`
+	Rank                int               `json:"rank"`
+	PreviousRank        int               `json:"previousRank"`
+	BuilderBaseTrophies int               `json:"builderBaseTrophies"`
+}
+
+// String returns a string representation of a player builder base ranking for a location
+func (l PlayerBuilderBaseRanking) String() string {
+	b, _ := json.Marshal(l)
+	return string(b)
+}
+
+// Statuses returned when verifying a player's API token
+const (
+	TokenStatusOK      = "ok"
+	TokenStatusInvalid = "invalid"
+)
+
+// PlayerTokenVerification is the result of verifying a player's API token, which the
+// player may find in the game's settings.
+type PlayerTokenVerification struct {
+	Tag    string `json:"tag"`
+	Token  string `json:"token"`
+	Status string `json:"status"`
+}
+
+// OK returns whether the token is valid for the player
+func (ptv PlayerTokenVerification) OK() bool {
+	return ptv.Status == TokenStatusOK
+}
+
+// String returns a string representation of a player token verification
+func (ptv PlayerTokenVerification) String() string {
+	b, _ := json.Marshal(ptv)
+	return string(b)
+}
+
 // GetPlayer retrieves information about a given player
 func GetPlayer(tag string) (*Player, error) {
+	return defaultClient.GetPlayer(tag)
+}
+
+// GetPlayerContext retrieves information about a given player
+func GetPlayerContext(ctx context.Context, tag string) (*Player, error) {
+	return defaultClient.GetPlayerContext(ctx, tag)
+}
+
+// GetPlayer retrieves information about a given player
+//
+// GetPlayer uses context.Background internally; to specify the context, use
+// GetPlayerContext.
+func (c *Client) GetPlayer(tag string) (*Player, error) {
+	return c.GetPlayerContext(context.Background(), tag)
+}
+
+// GetPlayerContext retrieves information about a given player
+func (c *Client) GetPlayerContext(ctx context.Context, tag string) (*Player, error) {
+	ctx, span := c.startSpan(ctx, "GetPlayer", "/players/{playerTag}", attribute.String("coc.tag", tag))
+	defer span.End()
+
 	// Build the URL
 	var sb strings.Builder
 	sb.Grow(100)
-	sb.WriteString(config.Data.BaseURL)
+	sb.WriteString(c.baseURL)
 	sb.WriteString("/players/")
 	sb.WriteString(fmtTag(tag))
 	url := sb.String()
-	log.Trace(url)
 
 	// Get the player
-	body, err := get(url, nil)
+	body, err := c.get(ctx, url, nil)
 	if err != nil {
+		if errors.Is(err, rest.ErrNotFound) {
+			return nil, ErrPlayerNotFound
+		}
 		return nil, err
 	}
 	var player Player
 	if err := json.Unmarshal(body, &player); err != nil {
-		log.Debug("failed to parse the json response")
+		c.log.Debug("failed to parse the json response", "error", err)
 		return nil, err
 	}
 
@@ -137,64 +204,286 @@ func GetPlayer(tag string) (*Player, error) {
 	return &player, nil
 }
 
+// VerifyPlayerToken verifies that the API token belongs to the given player
+func VerifyPlayerToken(tag string, token string) (*PlayerTokenVerification, error) {
+	return defaultClient.VerifyPlayerToken(tag, token)
+}
+
+// VerifyPlayerTokenContext verifies that the API token belongs to the given player
+func VerifyPlayerTokenContext(ctx context.Context, tag string, token string) (*PlayerTokenVerification, error) {
+	return defaultClient.VerifyPlayerTokenContext(ctx, tag, token)
+}
+
+// VerifyPlayerToken verifies that the API token belongs to the given player
+//
+// VerifyPlayerToken uses context.Background internally; to specify the context, use
+// VerifyPlayerTokenContext.
+func (c *Client) VerifyPlayerToken(tag string, token string) (*PlayerTokenVerification, error) {
+	return c.VerifyPlayerTokenContext(context.Background(), tag, token)
+}
+
+// VerifyPlayerTokenContext verifies that the API token belongs to the given player
+func (c *Client) VerifyPlayerTokenContext(ctx context.Context, tag string, token string) (*PlayerTokenVerification, error) {
+	ctx, span := c.startSpan(ctx, "VerifyPlayerToken", "/players/{playerTag}/verifytoken", attribute.String("coc.tag", tag))
+	defer span.End()
+
+	// Build the URL
+	var sb strings.Builder
+	sb.Grow(100)
+	sb.WriteString(c.baseURL)
+	sb.WriteString("/players/")
+	sb.WriteString(fmtTag(tag))
+	sb.WriteString("/verifytoken")
+	url := sb.String()
+
+	// Verify the token
+	body, err := c.post(ctx, url, map[string]string{"token": token})
+	if err != nil {
+		if errors.Is(err, rest.ErrNotFound) {
+			return nil, ErrPlayerNotFound
+		}
+		return nil, err
+	}
+	var verification PlayerTokenVerification
+	if err := json.Unmarshal(body, &verification); err != nil {
+		c.log.Debug("failed to parse the json response", "error", err)
+		return nil, err
+	}
+
+	// Return the result of the verification
+	return &verification, nil
+}
+
+// GetPlayerRankings gets player rankings for a specific location
+func GetPlayerRankings(locationID string, opts ...RequestOption) ([]PlayerRanking, error) {
+	return defaultClient.GetPlayerRankings(locationID, opts...)
+}
+
+// GetPlayerRankingsContext gets player rankings for a specific location
+func GetPlayerRankingsContext(ctx context.Context, locationID string, opts ...RequestOption) ([]PlayerRanking, error) {
+	return defaultClient.GetPlayerRankingsContext(ctx, locationID, opts...)
+}
+
+// GetPlayerRankingsPage returns a single page of player rankings, along with the
+// cursors of the pages before and after it
+func GetPlayerRankingsPage(ctx context.Context, locationID string, opts ...RequestOption) (*Page[PlayerRanking], error) {
+	return defaultClient.GetPlayerRankingsPage(ctx, locationID, opts...)
+}
+
+// IteratePlayerRankings returns an iterator over the player rankings, fetching pages
+// as they are needed. At most maxItems items are returned; if maxItems is zero,
+// every item is returned.
+func IteratePlayerRankings(ctx context.Context, locationID string, maxItems int, opts ...RequestOption) *Iterator[PlayerRanking] {
+	return defaultClient.IteratePlayerRankings(ctx, locationID, maxItems, opts...)
+}
+
 // GetPlayerRankings gets player rankings for a specific location
-func GetPlayerRankings(locationID string, qparms rest.QParms) ([]PlayerRanking, error) {
+//
+// GetPlayerRankings uses context.Background internally; to specify the context, use
+// GetPlayerRankingsContext.
+func (c *Client) GetPlayerRankings(locationID string, opts ...RequestOption) ([]PlayerRanking, error) {
+	return c.GetPlayerRankingsContext(context.Background(), locationID, opts...)
+}
+
+// GetPlayerRankingsContext gets player rankings for a specific location
+func (c *Client) GetPlayerRankingsContext(ctx context.Context, locationID string, opts ...RequestOption) ([]PlayerRanking, error) {
+	page, err := c.GetPlayerRankingsPage(ctx, locationID, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return page.Items, nil
+}
+
+// GetPlayerRankingsPage returns a single page of player rankings, along with the
+// cursors of the pages before and after it
+func (c *Client) GetPlayerRankingsPage(ctx context.Context, locationID string, opts ...RequestOption) (*Page[PlayerRanking], error) {
+	ctx, span := c.startSpan(ctx, "GetPlayerRankings", "/locations/{locationId}/rankings/players", attribute.String("coc.location_id", locationID))
+	defer span.End()
+
 	var sb strings.Builder
 	sb.Grow(100)
-	sb.WriteString(config.Data.BaseURL)
+	sb.WriteString(c.baseURL)
 	sb.WriteString("/locations/")
-	sb.WriteString(fmtTag(locationID))
+	sb.WriteString(locationID)
 	sb.WriteString("/rankings/players")
 	url := sb.String()
-	log.Trace(url)
 
-	body, err := get(url, nil)
+	body, err := c.get(ctx, url, requestParms(opts))
 	if err != nil {
 		return nil, err
 	}
 
-	// Parse into an array of clans
-	type respType struct {
-		Rankings []PlayerRanking `json:"items"`
+	// Parse into a page of player rankings
+	var page Page[PlayerRanking]
+	if err := json.Unmarshal(body, &page); err != nil {
+		c.log.Debug("failed to parse the json response", "error", err)
+		return nil, err
 	}
-	var resp respType
-	err = json.Unmarshal(body, &resp)
+
+	return &page, nil
+}
+
+// IteratePlayerRankings returns an iterator over the player rankings, fetching pages
+// as they are needed. At most maxItems items are returned; if maxItems is zero,
+// every item is returned.
+func (c *Client) IteratePlayerRankings(ctx context.Context, locationID string, maxItems int, opts ...RequestOption) *Iterator[PlayerRanking] {
+	return newIterator(ctx, maxItems, opts, func(ctx context.Context, opts ...RequestOption) (*Page[PlayerRanking], error) {
+		return c.GetPlayerRankingsPage(ctx, locationID, opts...)
+	})
+}
+
+// GetPlayerVersusRankings gets player versus rankings for a specific location
+func GetPlayerVersusRankings(locationID string, opts ...RequestOption) ([]PlayerVersusRanking, error) {
+	return defaultClient.GetPlayerVersusRankings(locationID, opts...)
+}
+
+// GetPlayerVersusRankingsContext gets player versus rankings for a specific location
+func GetPlayerVersusRankingsContext(ctx context.Context, locationID string, opts ...RequestOption) ([]PlayerVersusRanking, error) {
+	return defaultClient.GetPlayerVersusRankingsContext(ctx, locationID, opts...)
+}
+
+// GetPlayerVersusRankingsPage returns a single page of player versus rankings, along
+// with the cursors of the pages before and after it
+func GetPlayerVersusRankingsPage(ctx context.Context, locationID string, opts ...RequestOption) (*Page[PlayerVersusRanking], error) {
+	return defaultClient.GetPlayerVersusRankingsPage(ctx, locationID, opts...)
+}
+
+// IteratePlayerVersusRankings returns an iterator over the player versus rankings,
+// fetching pages as they are needed. At most maxItems items are returned; if
+// maxItems is zero, every item is returned.
+func IteratePlayerVersusRankings(ctx context.Context, locationID string, maxItems int, opts ...RequestOption) *Iterator[PlayerVersusRanking] {
+	return defaultClient.IteratePlayerVersusRankings(ctx, locationID, maxItems, opts...)
+}
+
+// GetPlayerVersusRankings gets player versus rankings for a specific location
+//
+// GetPlayerVersusRankings uses context.Background internally; to specify the
+// context, use GetPlayerVersusRankingsContext.
+func (c *Client) GetPlayerVersusRankings(locationID string, opts ...RequestOption) ([]PlayerVersusRanking, error) {
+	return c.GetPlayerVersusRankingsContext(context.Background(), locationID, opts...)
+}
+
+// GetPlayerVersusRankingsContext gets player versus rankings for a specific location
+func (c *Client) GetPlayerVersusRankingsContext(ctx context.Context, locationID string, opts ...RequestOption) ([]PlayerVersusRanking, error) {
+	page, err := c.GetPlayerVersusRankingsPage(ctx, locationID, opts...)
 	if err != nil {
-		log.Debug("failed to parse the json response")
 		return nil, err
 	}
-
-	// Return the clan
-	return resp.Rankings, nil
+	return page.Items, nil
 }
 
-// GetPlayerRankings gets clan versus rankings for a specific location
-func GetPlayerVersusRankings(locationID string, qparms rest.QParms) ([]PlayerVersusRanking, error) {
+// GetPlayerVersusRankingsPage returns a single page of player versus rankings, along
+// with the cursors of the pages before and after it
+func (c *Client) GetPlayerVersusRankingsPage(ctx context.Context, locationID string, opts ...RequestOption) (*Page[PlayerVersusRanking], error) {
+	ctx, span := c.startSpan(ctx, "GetPlayerVersusRankings", "/locations/{locationId}/rankings/players-versus", attribute.String("coc.location_id", locationID))
+	defer span.End()
+
 	var sb strings.Builder
 	sb.Grow(100)
-	sb.WriteString(config.Data.BaseURL)
+	sb.WriteString(c.baseURL)
 	sb.WriteString("/locations/")
-	sb.WriteString(fmtTag(locationID))
-	sb.WriteString("/rankings/clan-versus")
+	sb.WriteString(locationID)
+	sb.WriteString("/rankings/players-versus")
 	url := sb.String()
-	log.Trace(url)
 
-	body, err := get(url, nil)
+	body, err := c.get(ctx, url, requestParms(opts))
 	if err != nil {
 		return nil, err
 	}
 
-	// Parse into an array of clan rankings
-	type respType struct {
-		Rankings []PlayerVersusRanking `json:"items"`
+	// Parse into a page of player versus rankings
+	var page Page[PlayerVersusRanking]
+	if err := json.Unmarshal(body, &page); err != nil {
+		c.log.Debug("failed to parse the json response", "error", err)
+		return nil, err
+	}
+
+	return &page, nil
+}
+
+// IteratePlayerVersusRankings returns an iterator over the player versus rankings,
+// fetching pages as they are needed. At most maxItems items are returned; if
+// maxItems is zero, every item is returned.
+func (c *Client) IteratePlayerVersusRankings(ctx context.Context, locationID string, maxItems int, opts ...RequestOption) *Iterator[PlayerVersusRanking] {
+	return newIterator(ctx, maxItems, opts, func(ctx context.Context, opts ...RequestOption) (*Page[PlayerVersusRanking], error) {
+		return c.GetPlayerVersusRankingsPage(ctx, locationID, opts...)
+	})
+}
+
+// GetPlayerBuilderBaseRankings gets player builder base rankings for a specific location
+func GetPlayerBuilderBaseRankings(locationID string, opts ...RequestOption) ([]PlayerBuilderBaseRanking, error) {
+	return defaultClient.GetPlayerBuilderBaseRankings(locationID, opts...)
+}
+
+// GetPlayerBuilderBaseRankingsContext gets player builder base rankings for a specific location
+func GetPlayerBuilderBaseRankingsContext(ctx context.Context, locationID string, opts ...RequestOption) ([]PlayerBuilderBaseRanking, error) {
+	return defaultClient.GetPlayerBuilderBaseRankingsContext(ctx, locationID, opts...)
+}
+
+// GetPlayerBuilderBaseRankingsPage returns a single page of player builder base
+// rankings, along with the cursors of the pages before and after it
+func GetPlayerBuilderBaseRankingsPage(ctx context.Context, locationID string, opts ...RequestOption) (*Page[PlayerBuilderBaseRanking], error) {
+	return defaultClient.GetPlayerBuilderBaseRankingsPage(ctx, locationID, opts...)
+}
+
+// IteratePlayerBuilderBaseRankings returns an iterator over the player builder base
+// rankings, fetching pages as they are needed. At most maxItems items are returned;
+// if maxItems is zero, every item is returned.
+func IteratePlayerBuilderBaseRankings(ctx context.Context, locationID string, maxItems int, opts ...RequestOption) *Iterator[PlayerBuilderBaseRanking] {
+	return defaultClient.IteratePlayerBuilderBaseRankings(ctx, locationID, maxItems, opts...)
+}
+
+// GetPlayerBuilderBaseRankings gets player builder base rankings for a specific location
+//
+// GetPlayerBuilderBaseRankings uses context.Background internally; to specify the
+// context, use GetPlayerBuilderBaseRankingsContext.
+func (c *Client) GetPlayerBuilderBaseRankings(locationID string, opts ...RequestOption) ([]PlayerBuilderBaseRanking, error) {
+	return c.GetPlayerBuilderBaseRankingsContext(context.Background(), locationID, opts...)
+}
+
+// GetPlayerBuilderBaseRankingsContext gets player builder base rankings for a specific location
+func (c *Client) GetPlayerBuilderBaseRankingsContext(ctx context.Context, locationID string, opts ...RequestOption) ([]PlayerBuilderBaseRanking, error) {
+	page, err := c.GetPlayerBuilderBaseRankingsPage(ctx, locationID, opts...)
+	if err != nil {
+		return nil, err
 	}
-	var resp respType
-	err = json.Unmarshal(body, &resp)
+	return page.Items, nil
+}
+
+// GetPlayerBuilderBaseRankingsPage returns a single page of player builder base
+// rankings, along with the cursors of the pages before and after it
+func (c *Client) GetPlayerBuilderBaseRankingsPage(ctx context.Context, locationID string, opts ...RequestOption) (*Page[PlayerBuilderBaseRanking], error) {
+	ctx, span := c.startSpan(ctx, "GetPlayerBuilderBaseRankings", "/locations/{locationId}/rankings/players-builder-base", attribute.String("coc.location_id", locationID))
+	defer span.End()
+
+	var sb strings.Builder
+	sb.Grow(100)
+	sb.WriteString(c.baseURL)
+	sb.WriteString("/locations/")
+	sb.WriteString(locationID)
+	sb.WriteString("/rankings/players-builder-base")
+	url := sb.String()
+
+	body, err := c.get(ctx, url, requestParms(opts))
 	if err != nil {
-		log.Debug("failed to parse the json response")
 		return nil, err
 	}
 
-	// Return the clan
-	return resp.Rankings, nil
+	// Parse into a page of player builder base rankings
+	var page Page[PlayerBuilderBaseRanking]
+	if err := json.Unmarshal(body, &page); err != nil {
+		c.log.Debug("failed to parse the json response", "error", err)
+		return nil, err
+	}
+
+	return &page, nil
+}
+
+// IteratePlayerBuilderBaseRankings returns an iterator over the player builder base
+// rankings, fetching pages as they are needed. At most maxItems items are returned;
+// if maxItems is zero, every item is returned.
+func (c *Client) IteratePlayerBuilderBaseRankings(ctx context.Context, locationID string, maxItems int, opts ...RequestOption) *Iterator[PlayerBuilderBaseRanking] {
+	return newIterator(ctx, maxItems, opts, func(ctx context.Context, opts ...RequestOption) (*Page[PlayerBuilderBaseRanking], error) {
+		return c.GetPlayerBuilderBaseRankingsPage(ctx, locationID, opts...)
+	})
 }
@@ -1,6 +1,8 @@
 package coc
 
 import (
+	"context"
+
 	"github.com/clashgolang/coc/pkg/rest"
 )
 
@@ -8,25 +10,54 @@ var (
 	defaultHeaders = rest.Headers{
 		"Accept": "application/json",
 	}
-	token string
 )
 
 // SetToken sets the token to be used on requests sent to Clash of Clans
 func SetToken(t string) {
-	token = t
+	defaultClient.SetToken(t)
 }
 
-// get retrieves the requested URL and return the results as a byte array.
-func get(url string, qparms rest.QParms) ([]byte, error) {
-	headers := rest.Headers{"Authorization": "Bearer " + token}
-	for k, v := range defaultHeaders {
-		headers[k] = v
-	}
-	client := rest.NewClient(headers, qparms)
+// SetTokens sets the tokens to be used on requests sent to Clash of Clans
+func SetTokens(tokens ...string) {
+	defaultClient.SetTokens(tokens...)
+}
+
+// get retrieves the requested URL and return the results as a byte array
+func (c *Client) get(ctx context.Context, url string, qparms rest.QParms) ([]byte, error) {
+	return c.send(ctx, func(client rest.Client) ([]byte, error) {
+		if qparms != nil {
+			client = client.WithQParms(qparms)
+		}
+		return client.GetContext(ctx, url)
+	})
+}
+
+// post sends the body, encoded as JSON, to the requested URL and returns the results
+// as a byte array
+func (c *Client) post(ctx context.Context, url string, body interface{}) ([]byte, error) {
+	return c.send(ctx, func(client rest.Client) ([]byte, error) {
+		return client.PostContext(ctx, url, body)
+	})
+}
 
-	body, err := client.Get(url)
-	if err != nil {
-		return nil, err
+// send sends a request using the REST client for one of the client's tokens. If the
+// token used is throttled or not valid for the current IP address, the request is
+// sent again using another of the client's tokens.
+func (c *Client) send(ctx context.Context, request func(rest.Client) ([]byte, error)) ([]byte, error) {
+	attempts := c.keys.size()
+	if attempts < 1 {
+		attempts = 1
+	}
+	for attempt := 1; ; attempt++ {
+		token := c.keys.pick()
+		body, err := request(c.restClient(token))
+		if err == nil {
+			return body, nil
+		}
+		if !c.keys.report(token, err) || attempt >= attempts {
+			recordError(ctx, err)
+			return nil, err
+		}
+		c.log.Debug("token benched, retrying the request with another token", "error", err)
 	}
-	return body, nil
 }
@@ -27,7 +27,7 @@ func (ct *CoCTime) UnmarshalJSON(b []byte) error {
 
 // MarshalJSON converts a CoCTime into a JSON string
 func (ct CoCTime) MarshalJSON() ([]byte, error) {
-	return json.Marshal(ct)
+	return json.Marshal(time.Time(ct).Format(cocTimeLayout))
 }
 
 // Format prints the date
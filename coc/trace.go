@@ -0,0 +1,31 @@
+package coc
+
+import (
+	"context"
+
+	"github.com/clashgolang/coc/pkg/rest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// tracerName is the name of the tracer used to create spans
+	tracerName = "github.com/clashgolang/coc/coc"
+)
+
+// startSpan starts a span for a call to an API endpoint. The endpoint template is
+// added to the returned context so that it is used to label metrics and the spans
+// of the HTTP requests sent for the call.
+func (c *Client) startSpan(ctx context.Context, name string, endpoint string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	ctx = rest.WithEndpoint(ctx, endpoint)
+	attrs = append(attrs, attribute.String("coc.endpoint", endpoint))
+	return c.tracer.Start(ctx, "coc."+name, trace.WithAttributes(attrs...))
+}
+
+// recordError records the error on the span carried by the context
+func recordError(ctx context.Context, err error) {
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
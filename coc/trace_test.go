@@ -0,0 +1,179 @@
+package coc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/clashgolang/coc/pkg/rest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// newTracedClient returns a client that records its spans in the returned exporter
+func newTracedClient(t *testing.T, baseURL string, options ...ClientOption) (*Client, *tracetest.InMemoryExporter, *sdktrace.TracerProvider) {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+
+	options = append([]ClientOption{WithBaseURL(baseURL), WithTracerProvider(provider)}, options...)
+	return NewClient("token", options...), exporter, provider
+}
+
+// spansNamed returns the recorded spans with the given name, in the order they ended
+func spansNamed(spans tracetest.SpanStubs, name string) tracetest.SpanStubs {
+	var named tracetest.SpanStubs
+	for _, span := range spans {
+		if span.Name == name {
+			named = append(named, span)
+		}
+	}
+	return named
+}
+
+// attr returns the value of the span attribute with the given key
+func attr(t *testing.T, span tracetest.SpanStub, key string) attribute.Value {
+	t.Helper()
+	for _, kv := range span.Attributes {
+		if string(kv.Key) == key {
+			return kv.Value
+		}
+	}
+	t.Fatalf("span %q has no attribute %q", span.Name, key)
+	return attribute.Value{}
+}
+
+func TestSpansForRetriedRequest(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Cache-Control", "public max-age=60")
+		w.Write([]byte(`{"tag":"#ABC","name":"player"}`))
+	}))
+	defer server.Close()
+
+	policy := rest.DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	client, exporter, provider := newTracedClient(t, server.URL,
+		WithRetryPolicy(policy),
+		WithCache(rest.NewLRUCache(10)),
+	)
+
+	ctx, caller := provider.Tracer("test").Start(context.Background(), "caller")
+	if _, err := client.GetPlayerContext(ctx, "#ABC"); err != nil {
+		t.Fatal(err)
+	}
+	caller.End()
+
+	spans := exporter.GetSpans()
+	calls := spansNamed(spans, "coc.GetPlayer")
+	if len(calls) != 1 {
+		t.Fatalf("got %d coc.GetPlayer spans, want 1", len(calls))
+	}
+	call := calls[0]
+	if call.Parent.SpanID() != caller.SpanContext().SpanID() {
+		t.Error("coc.GetPlayer span is not a child of the caller's span")
+	}
+	if call.SpanContext.TraceID() != caller.SpanContext().TraceID() {
+		t.Error("coc.GetPlayer span is not in the caller's trace")
+	}
+	if got := attr(t, call, "coc.endpoint").AsString(); got != "/players/{playerTag}" {
+		t.Errorf("got coc.endpoint %q, want /players/{playerTag}", got)
+	}
+	if got := attr(t, call, "coc.tag").AsString(); got != "#ABC" {
+		t.Errorf("got coc.tag %q, want #ABC", got)
+	}
+	if got := attr(t, call, "coc.retry_count").AsInt64(); got != 1 {
+		t.Errorf("got coc.retry_count %d, want 1", got)
+	}
+	if attr(t, call, "coc.cache_hit").AsBool() {
+		t.Error("got coc.cache_hit true, want false")
+	}
+
+	attempts := spansNamed(spans, "HTTP GET")
+	if len(attempts) != 2 {
+		t.Fatalf("got %d HTTP GET spans, want 2", len(attempts))
+	}
+	for i, want := range []int64{http.StatusInternalServerError, http.StatusOK} {
+		attempt := attempts[i]
+		if attempt.Parent.SpanID() != call.SpanContext.SpanID() {
+			t.Errorf("attempt %d is not a child of the coc.GetPlayer span", i+1)
+		}
+		if got := attr(t, attempt, "coc.attempt").AsInt64(); got != int64(i+1) {
+			t.Errorf("attempt %d: got coc.attempt %d", i+1, got)
+		}
+		if got := attr(t, attempt, "http.response.status_code").AsInt64(); got != want {
+			t.Errorf("attempt %d: got http.response.status_code %d, want %d", i+1, got, want)
+		}
+		if got := attr(t, attempt, "coc.endpoint").AsString(); got != "/players/{playerTag}" {
+			t.Errorf("attempt %d: got coc.endpoint %q, want /players/{playerTag}", i+1, got)
+		}
+	}
+	if attempts[0].Status.Code != codes.Error {
+		t.Error("failed attempt does not have an error status")
+	}
+}
+
+func TestSpansForCachedResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "public max-age=60")
+		w.Write([]byte(`{"tag":"#ABC","name":"clan"}`))
+	}))
+	defer server.Close()
+
+	client, exporter, _ := newTracedClient(t, server.URL, WithCache(rest.NewLRUCache(10)))
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetClanContext(context.Background(), "#ABC"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	spans := exporter.GetSpans()
+	calls := spansNamed(spans, "coc.GetClan")
+	if len(calls) != 2 {
+		t.Fatalf("got %d coc.GetClan spans, want 2", len(calls))
+	}
+	if attr(t, calls[0], "coc.cache_hit").AsBool() {
+		t.Error("first call: got coc.cache_hit true, want false")
+	}
+	if !attr(t, calls[1], "coc.cache_hit").AsBool() {
+		t.Error("second call: got coc.cache_hit false, want true")
+	}
+	if got := len(spansNamed(spans, "HTTP GET")); got != 1 {
+		t.Errorf("got %d HTTP GET spans, want 1 as the second call is served from the cache", got)
+	}
+}
+
+func TestSpanRecordsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"reason":"notFound"}`))
+	}))
+	defer server.Close()
+
+	client, exporter, _ := newTracedClient(t, server.URL)
+	if _, err := client.GetPlayerContext(context.Background(), "#ABC"); !errors.Is(err, ErrPlayerNotFound) {
+		t.Fatalf("got %v, want ErrPlayerNotFound", err)
+	}
+
+	calls := spansNamed(exporter.GetSpans(), "coc.GetPlayer")
+	if len(calls) != 1 {
+		t.Fatalf("got %d coc.GetPlayer spans, want 1", len(calls))
+	}
+	if calls[0].Status.Code != codes.Error {
+		t.Errorf("got status %v, want an error", calls[0].Status.Code)
+	}
+	if len(calls[0].Events) == 0 {
+		t.Error("the error was not recorded on the span")
+	}
+}
@@ -62,7 +62,7 @@ func main() {
 // getClanLabels lists clan labels
 func getClanLabels(c *cli.Context) error {
 	// Get the clan labels
-	labels, err := coc.GetClanLabels(nil)
+	labels, err := coc.GetClanLabels()
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
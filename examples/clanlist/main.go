@@ -72,7 +72,7 @@ func getClanList(c *cli.Context) error {
 	name := c.String("clan")
 
 	// Get the clan wars
-	clans, err := coc.GetClans(name, nil)
+	clans, err := coc.GetClans(name)
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
@@ -72,7 +72,7 @@ func getClanMembers(c *cli.Context) error {
 	tag := c.String("clantag")
 
 	// Get the clan wars
-	members, err := coc.GetClanMembers(tag, nil)
+	members, err := coc.GetClanMembers(tag)
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
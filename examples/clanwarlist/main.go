@@ -72,7 +72,7 @@ func getWarList(c *cli.Context) error {
 	tag := c.String("clantag")
 
 	// Get the clan wars
-	warList, err := coc.GetClanWars(tag, nil)
+	warList, err := coc.GetClanWars(tag)
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
@@ -62,7 +62,7 @@ func main() {
 // getPlayerLabels lists clan labels
 func getPlayerLabels(c *cli.Context) error {
 	// Get the player labels
-	labels, err := coc.GetPlayerLabels(nil)
+	labels, err := coc.GetPlayerLabels()
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
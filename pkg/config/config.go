@@ -3,9 +3,8 @@ package config
 import (
 	"encoding/json"
 	"io/ioutil"
+	"log/slog"
 	"os"
-
-	log "github.com/sirupsen/logrus"
 )
 
 var (
@@ -26,7 +25,7 @@ type config struct {
 func init() {
 	jsonFile, err := os.Open(configFile)
 	if err != nil {
-		log.Errorf("unable to open file %s", configFile)
+		slog.Debug("unable to open the configuration file, using the defaults", "file", configFile, "error", err)
 		// Use the default values for the configuration
 		Data = config{
 			BaseURL: defaultBaseURL,
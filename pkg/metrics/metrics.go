@@ -0,0 +1,109 @@
+// Package metrics records Prometheus metrics for the requests sent to the Clash of
+// Clans API.
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	defaultNamespace = "coc"
+)
+
+// Collector records measurements of API requests and exposes them as Prometheus
+// metrics. It implements both rest.Metrics and prometheus.Collector, so it may be
+// passed to a client and registered with a Prometheus registry.
+type Collector struct {
+	requests      *prometheus.CounterVec
+	duration      *prometheus.HistogramVec
+	retries       *prometheus.CounterVec
+	cache         *prometheus.CounterVec
+	rateLimitWait *prometheus.HistogramVec
+}
+
+// NewCollector creates a collector whose metrics are prefixed with the namespace. If
+// the namespace is empty, "coc" is used.
+func NewCollector(namespace string) *Collector {
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "Number of requests sent to the Clash of Clans API, by endpoint and status code.",
+		}, []string{"endpoint", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Latency of requests sent to the Clash of Clans API, by endpoint.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"endpoint"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "retries_total",
+			Help:      "Number of requests to the Clash of Clans API that were retried, by endpoint.",
+		}, []string{"endpoint"}),
+		cache: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_requests_total",
+			Help:      "Number of cache lookups for Clash of Clans API responses, by endpoint and result.",
+		}, []string{"endpoint", "result"}),
+		rateLimitWait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "rate_limit_wait_seconds",
+			Help:      "Time requests to the Clash of Clans API waited for the client-side rate limiter, by endpoint.",
+			Buckets:   []float64{0, .01, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		}, []string{"endpoint"}),
+	}
+}
+
+// ObserveRequest records a single attempt to send a request
+func (c *Collector) ObserveRequest(endpoint string, statusCode int, duration time.Duration) {
+	code := "error"
+	if statusCode != 0 {
+		code = strconv.Itoa(statusCode)
+	}
+	c.requests.WithLabelValues(endpoint, code).Inc()
+	c.duration.WithLabelValues(endpoint).Observe(duration.Seconds())
+}
+
+// ObserveRetry records that a request is being retried
+func (c *Collector) ObserveRetry(endpoint string) {
+	c.retries.WithLabelValues(endpoint).Inc()
+}
+
+// ObserveCache records whether a request was served from the cache
+func (c *Collector) ObserveCache(endpoint string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	c.cache.WithLabelValues(endpoint, result).Inc()
+}
+
+// ObserveRateLimitWait records how long a request waited for the rate limiter
+func (c *Collector) ObserveRateLimitWait(endpoint string, wait time.Duration) {
+	c.rateLimitWait.WithLabelValues(endpoint).Observe(wait.Seconds())
+}
+
+// Describe sends the descriptors of the collector's metrics to the channel
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.requests.Describe(ch)
+	c.duration.Describe(ch)
+	c.retries.Describe(ch)
+	c.cache.Describe(ch)
+	c.rateLimitWait.Describe(ch)
+}
+
+// Collect sends the current values of the collector's metrics to the channel
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.requests.Collect(ch)
+	c.duration.Collect(ch)
+	c.retries.Collect(ch)
+	c.cache.Collect(ch)
+	c.rateLimitWait.Collect(ch)
+}
@@ -0,0 +1,229 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// BreakerState is the state of a circuit breaker
+type BreakerState int
+
+const (
+	// BreakerClosed lets all requests through
+	BreakerClosed BreakerState = iota
+	// BreakerOpen fails all requests without sending them to the server
+	BreakerOpen
+	// BreakerHalfOpen lets a single probe request through to find out whether the
+	// server has recovered
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("BreakerState(%d)", int(s))
+}
+
+// ErrCircuitOpen is returned, without contacting the server, when a request is made
+// while the circuit breaker is open
+type ErrCircuitOpen struct {
+	// Until is the time at which the breaker lets a probe request through
+	Until time.Time
+}
+
+func (err ErrCircuitOpen) Error() string {
+	return fmt.Sprintf("circuit breaker is open until %s", err.Until.Format(time.RFC3339))
+}
+
+// BreakerConfig configures when a circuit breaker opens and how long it stays open
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the breaker
+	FailureThreshold int
+	// OpenTimeout is how long the breaker stays open before it lets a probe through
+	OpenTimeout time.Duration
+	// OnStateChange, if set, is called each time the breaker changes state. Calls are
+	// made one at a time, in the order the changes happen, though not necessarily from
+	// the goroutine whose request caused the change.
+	OnStateChange func(from, to BreakerState)
+}
+
+// DefaultBreakerConfig returns a configuration that opens the breaker after 5
+// consecutive failures and probes the server again after 30 seconds.
+func DefaultBreakerConfig() BreakerConfig {
+	return BreakerConfig{
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+	}
+}
+
+// CircuitBreaker stops requests from being sent to a server that is failing. The
+// breaker opens after a number of consecutive failures, or as soon as the server
+// reports that it is in maintenance. While open, requests fail fast with
+// ErrCircuitOpen. Once the open timeout has passed, a single probe request is let
+// through; if it succeeds the breaker closes, otherwise it opens again.
+//
+// Network errors and server errors (5xx) are failures. Other error responses, such
+// as a player that is not found, show that the server is up and count as successes.
+// A CircuitBreaker is safe for concurrent use and may be shared across clients.
+type CircuitBreaker struct {
+	config   BreakerConfig
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	until    time.Time
+	probing  bool
+
+	// changes holds the state changes not yet passed to OnStateChange, and notifying
+	// is set while a goroutine is passing them on
+	changes   []stateChange
+	notifying bool
+}
+
+// stateChange is a change of state waiting to be passed to the callback
+type stateChange struct {
+	from, to BreakerState
+}
+
+// NewCircuitBreaker creates a circuit breaker in the closed state
+func NewCircuitBreaker(config BreakerConfig) *CircuitBreaker {
+	if config.FailureThreshold < 1 {
+		config.FailureThreshold = 1
+	}
+	return &CircuitBreaker{config: config}
+}
+
+// State returns the current state of the breaker
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerOpen && !time.Now().Before(b.until) {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+// allow returns an ErrCircuitOpen if a request may not be sent. When the open timeout
+// has passed, the first caller is let through as the probe.
+func (b *CircuitBreaker) allow() error {
+	b.mu.Lock()
+	from := b.state
+	until := b.until
+	switch b.state {
+	case BreakerOpen:
+		if time.Now().Before(until) {
+			b.mu.Unlock()
+			return ErrCircuitOpen{Until: until}
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+	case BreakerHalfOpen:
+		if b.probing {
+			b.mu.Unlock()
+			return ErrCircuitOpen{Until: until}
+		}
+		b.probing = true
+	}
+	changed := b.changed(from, b.state)
+	b.mu.Unlock()
+
+	if changed {
+		b.notify()
+	}
+	return nil
+}
+
+// record updates the breaker with the outcome of a request that it allowed
+func (b *CircuitBreaker) record(ctx context.Context, err error) {
+	b.mu.Lock()
+	from := b.state
+	b.probing = false
+	switch {
+	case ignoredByBreaker(ctx, err):
+		// Nothing was learned about the server; if this was the probe, the next
+		// request becomes the probe instead
+	case !breakerFailure(err):
+		b.failures = 0
+		b.state = BreakerClosed
+	case errors.Is(err, ErrMaintenance) || b.state == BreakerHalfOpen:
+		b.open()
+	default:
+		b.failures++
+		if b.failures >= b.config.FailureThreshold {
+			b.open()
+		}
+	}
+	changed := b.changed(from, b.state)
+	b.mu.Unlock()
+
+	if changed {
+		b.notify()
+	}
+}
+
+// open opens the breaker for the open timeout. The caller must hold the lock.
+func (b *CircuitBreaker) open() {
+	b.state = BreakerOpen
+	b.failures = 0
+	b.until = time.Now().Add(b.config.OpenTimeout)
+}
+
+// changed queues a call to the state change callback if the state has changed, and
+// reports whether it did. The caller must hold the lock.
+func (b *CircuitBreaker) changed(from, to BreakerState) bool {
+	if from == to || b.config.OnStateChange == nil {
+		return false
+	}
+	b.changes = append(b.changes, stateChange{from: from, to: to})
+	return true
+}
+
+// notify passes the queued state changes to the callback in the order they happened.
+// If another goroutine is already doing so, it passes on these changes as well.
+func (b *CircuitBreaker) notify() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.notifying {
+		return
+	}
+	b.notifying = true
+	for len(b.changes) > 0 {
+		change := b.changes[0]
+		b.changes = b.changes[1:]
+		b.mu.Unlock()
+		b.config.OnStateChange(change.from, change.to)
+		b.mu.Lock()
+	}
+	b.changes = nil
+	b.notifying = false
+}
+
+// breakerFailure reports whether the error shows that the server is failing
+func breakerFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	var httpErr ErrHttp
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= http.StatusInternalServerError || httpErr.Reason == ReasonInMaintenance
+	}
+	return true
+}
+
+// ignoredByBreaker reports whether the error says nothing about the health of the
+// server, because the request was never sent or the caller gave up on it
+func ignoredByBreaker(ctx context.Context, err error) bool {
+	if err == nil {
+		return false
+	}
+	return ctx.Err() != nil || errors.Is(err, ErrRateLimited) || errors.As(err, &interceptorError{})
+}
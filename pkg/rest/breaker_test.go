@@ -0,0 +1,316 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newBreakerServer starts a server that responds with the status code and reason
+// currently stored in status, and counts the requests it receives
+func newBreakerServer(t *testing.T, status *atomic.Int32, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		code := int(status.Load())
+		w.WriteHeader(code)
+		switch code {
+		case http.StatusOK:
+			w.Write([]byte(`{}`))
+		case http.StatusServiceUnavailable:
+			w.Write([]byte(`{"reason":"inMaintenance"}`))
+		default:
+			w.Write([]byte(`{"reason":"unknownException"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCircuitBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	var status, requests atomic.Int32
+	status.Store(http.StatusInternalServerError)
+	server := newBreakerServer(t, &status, &requests)
+
+	breaker := NewCircuitBreaker(BreakerConfig{FailureThreshold: 3, OpenTimeout: time.Minute})
+	client := NewClient(nil, nil, WithCircuitBreaker(breaker))
+
+	for i := 0; i < 3; i++ {
+		_, err := client.Get(server.URL)
+		var httpErr ErrHttp
+		if !errors.As(err, &httpErr) {
+			t.Fatalf("request %d: got %v, want an ErrHttp", i+1, err)
+		}
+	}
+	if state := breaker.State(); state != BreakerOpen {
+		t.Fatalf("got state %s, want open", state)
+	}
+
+	_, err := client.Get(server.URL)
+	var openErr ErrCircuitOpen
+	if !errors.As(err, &openErr) {
+		t.Fatalf("got %v, want an ErrCircuitOpen", err)
+	}
+	if openErr.Until.Before(time.Now()) {
+		t.Errorf("got Until %s, want a time in the future", openErr.Until)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("server received %d requests, want 3", got)
+	}
+}
+
+func TestCircuitBreakerIgnoresClientErrors(t *testing.T) {
+	var status, requests atomic.Int32
+	status.Store(http.StatusNotFound)
+	server := newBreakerServer(t, &status, &requests)
+
+	breaker := NewCircuitBreaker(BreakerConfig{FailureThreshold: 1, OpenTimeout: time.Minute})
+	client := NewClient(nil, nil, WithCircuitBreaker(breaker))
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.Get(server.URL); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("request %d: got %v, want ErrNotFound", i+1, err)
+		}
+	}
+	if state := breaker.State(); state != BreakerClosed {
+		t.Errorf("got state %s, want closed", state)
+	}
+}
+
+func TestCircuitBreakerOpensOnMaintenance(t *testing.T) {
+	var status, requests atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+	server := newBreakerServer(t, &status, &requests)
+
+	breaker := NewCircuitBreaker(BreakerConfig{FailureThreshold: 10, OpenTimeout: time.Minute})
+	client := NewClient(nil, nil, WithCircuitBreaker(breaker), WithRetryPolicy(DefaultRetryPolicy()))
+
+	if _, err := client.Get(server.URL); !errors.Is(err, ErrMaintenance) {
+		t.Fatalf("got %v, want ErrMaintenance", err)
+	}
+	if state := breaker.State(); state != BreakerOpen {
+		t.Errorf("got state %s, want open", state)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("server received %d requests, want 1 as retries stop once the breaker opens", got)
+	}
+}
+
+func TestCircuitBreakerProbesWhenHalfOpen(t *testing.T) {
+	var status, requests atomic.Int32
+	status.Store(http.StatusInternalServerError)
+	server := newBreakerServer(t, &status, &requests)
+
+	var mu sync.Mutex
+	var changes []string
+	breaker := NewCircuitBreaker(BreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      20 * time.Millisecond,
+		OnStateChange: func(from, to BreakerState) {
+			mu.Lock()
+			defer mu.Unlock()
+			changes = append(changes, from.String()+"->"+to.String())
+		},
+	})
+	client := NewClient(nil, nil, WithCircuitBreaker(breaker))
+
+	// The failure opens the breaker, and the failed probe opens it again
+	client.Get(server.URL)
+	time.Sleep(30 * time.Millisecond)
+	if state := breaker.State(); state != BreakerHalfOpen {
+		t.Fatalf("got state %s, want half-open", state)
+	}
+	client.Get(server.URL)
+	if state := breaker.State(); state != BreakerOpen {
+		t.Fatalf("got state %s after a failed probe, want open", state)
+	}
+
+	// The successful probe closes the breaker
+	status.Store(http.StatusOK)
+	time.Sleep(30 * time.Millisecond)
+	if _, err := client.Get(server.URL); err != nil {
+		t.Fatalf("got %v, want the probe to succeed", err)
+	}
+	if state := breaker.State(); state != BreakerClosed {
+		t.Fatalf("got state %s after a successful probe, want closed", state)
+	}
+
+	want := []string{
+		"closed->open",
+		"open->half-open", "half-open->open",
+		"open->half-open", "half-open->closed",
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(changes) != len(want) {
+		t.Fatalf("got state changes %v, want %v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Fatalf("got state changes %v, want %v", changes, want)
+		}
+	}
+}
+
+// TestCircuitBreakerConcurrent sends requests from many goroutines while the breaker
+// opens, probes and reopens. It is meant to be run with -race.
+func TestCircuitBreakerConcurrent(t *testing.T) {
+	var status, requests atomic.Int32
+	status.Store(http.StatusInternalServerError)
+	server := newBreakerServer(t, &status, &requests)
+
+	var changes atomic.Int32
+	breaker := NewCircuitBreaker(BreakerConfig{
+		FailureThreshold: 3,
+		OpenTimeout:      5 * time.Millisecond,
+		OnStateChange: func(from, to BreakerState) {
+			if from == to {
+				t.Errorf("state change from %s to itself", from)
+			}
+			changes.Add(1)
+		},
+	})
+	client := NewClient(nil, nil, WithCircuitBreaker(breaker), WithCoalescing(nil))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			deadline := time.Now().Add(100 * time.Millisecond)
+			for time.Now().Before(deadline) {
+				_, err := client.Get(server.URL)
+				var httpErr ErrHttp
+				var openErr ErrCircuitOpen
+				if !errors.As(err, &httpErr) && !errors.As(err, &openErr) {
+					t.Errorf("got %v, want an ErrHttp or ErrCircuitOpen", err)
+					return
+				}
+				breaker.State()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if changes.Load() < 3 {
+		t.Errorf("got %d state changes, want the breaker to open and probe", changes.Load())
+	}
+
+	// Once the server recovers, the next probe closes the breaker
+	status.Store(http.StatusOK)
+	time.Sleep(10 * time.Millisecond)
+	if _, err := client.Get(server.URL); err != nil {
+		t.Fatalf("got %v, want the probe to succeed", err)
+	}
+	if state := breaker.State(); state != BreakerClosed {
+		t.Errorf("got state %s, want closed", state)
+	}
+}
+
+// TestCircuitBreakerConcurrentTransitions moves the breaker between open and half-open
+// as fast as possible from many goroutines, checking that only one probe is let
+// through at a time. It is meant to be run with -race.
+func TestCircuitBreakerConcurrentTransitions(t *testing.T) {
+	breaker := NewCircuitBreaker(BreakerConfig{FailureThreshold: 1, OpenTimeout: time.Microsecond})
+	failure := ErrHttp{StatusCode: http.StatusInternalServerError}
+
+	// Open the breaker; as every probe fails, it never closes again
+	breaker.allow()
+	breaker.record(context.Background(), failure)
+
+	var inFlight, rejected atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 2000; j++ {
+				err := breaker.allow()
+				if err != nil {
+					var openErr ErrCircuitOpen
+					if !errors.As(err, &openErr) || openErr.Until.IsZero() {
+						t.Errorf("got %v, want an ErrCircuitOpen with the time it ends", err)
+						return
+					}
+					rejected.Add(1)
+					continue
+				}
+				if n := inFlight.Add(1); n > 1 {
+					t.Errorf("%d probes let through at the same time, want 1", n)
+				}
+				inFlight.Add(-1)
+				breaker.record(context.Background(), failure)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if rejected.Load() == 0 {
+		t.Error("no requests were rejected, want the breaker to open")
+	}
+}
+
+// TestCircuitBreakerOrdersStateChanges moves the breaker through all of its states
+// from many goroutines, checking that each change reported starts from the state the
+// previous one ended in. It is meant to be run with -race.
+func TestCircuitBreakerOrdersStateChanges(t *testing.T) {
+	var mu sync.Mutex
+	var changes []stateChange
+	var calling atomic.Int32
+	breaker := NewCircuitBreaker(BreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      time.Microsecond,
+		OnStateChange: func(from, to BreakerState) {
+			if calling.Add(1) > 1 {
+				t.Error("state change callback called while another call is in progress")
+			}
+			defer calling.Add(-1)
+			runtime.Gosched()
+			mu.Lock()
+			defer mu.Unlock()
+			changes = append(changes, stateChange{from: from, to: to})
+		},
+	})
+	failure := ErrHttp{StatusCode: http.StatusInternalServerError}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				if breaker.allow() != nil {
+					continue
+				}
+				if (i+j)%3 == 0 {
+					breaker.record(context.Background(), nil)
+				} else {
+					breaker.record(context.Background(), failure)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(changes) < 3 {
+		t.Fatalf("got %d state changes, want the breaker to open, probe and close", len(changes))
+	}
+	state := BreakerClosed
+	for i, change := range changes {
+		if change.from != state || change.from == change.to {
+			t.Fatalf("state change %d: got %s->%s after the breaker became %s", i, change.from, change.to, state)
+		}
+		state = change.to
+	}
+	if breaker.state != state {
+		t.Errorf("the last state change was to %s, want the breaker's state %s", state, breaker.state)
+	}
+}
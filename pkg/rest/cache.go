@@ -0,0 +1,139 @@
+package rest
+
+import (
+	"container/list"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Cache stores the bodies of responses so that identical requests may be served
+// without contacting the server. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the body cached for the key, if it has not expired
+	Get(key string) ([]byte, bool)
+	// Set caches the body for the key until the time to live has passed
+	Set(key string, body []byte, ttl time.Duration)
+}
+
+// CacheStats are the hit and miss counts of a cache
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// LRUCache is an in-memory cache that holds up to a fixed number of responses,
+// evicting the least recently used one when it is full.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  *list.List
+	items    map[string]*list.Element
+	hits     uint64
+	misses   uint64
+}
+
+// cacheEntry is a single response held by an LRU cache
+type cacheEntry struct {
+	key     string
+	body    []byte
+	expires time.Time
+}
+
+// NewLRUCache creates an in-memory cache that holds up to capacity responses
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LRUCache{
+		capacity: capacity,
+		entries:  list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// Get returns the body cached for the key, if it has not expired
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		atomic.AddUint64(&c.misses, 1)
+		return nil, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if time.Now().After(entry.expires) {
+		c.entries.Remove(elem)
+		delete(c.items, key)
+		atomic.AddUint64(&c.misses, 1)
+		return nil, false
+	}
+	c.entries.MoveToFront(elem)
+	atomic.AddUint64(&c.hits, 1)
+	return entry.body, true
+}
+
+// Set caches the body for the key until the time to live has passed
+func (c *LRUCache) Set(key string, body []byte, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := time.Now().Add(ttl)
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		entry.body = body
+		entry.expires = expires
+		c.entries.MoveToFront(elem)
+		return
+	}
+	c.items[key] = c.entries.PushFront(&cacheEntry{key: key, body: body, expires: expires})
+	for c.entries.Len() > c.capacity {
+		oldest := c.entries.Back()
+		c.entries.Remove(oldest)
+		delete(c.items, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// Len returns the number of responses held by the cache
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries.Len()
+}
+
+// Stats returns the number of cache hits and misses
+func (c *LRUCache) Stats() CacheStats {
+	return CacheStats{
+		Hits:   atomic.LoadUint64(&c.hits),
+		Misses: atomic.LoadUint64(&c.misses),
+	}
+}
+
+// maxAge returns how long a response may be cached according to the value of its
+// Cache-Control header. Clash of Clans separates the directives with spaces rather
+// than commas, so both are accepted.
+func maxAge(cacheControl string) time.Duration {
+	var age time.Duration
+	directives := strings.FieldsFunc(cacheControl, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	for _, directive := range directives {
+		directive = strings.ToLower(directive)
+		switch {
+		case directive == "no-store" || directive == "no-cache":
+			return 0
+		case strings.HasPrefix(directive, "max-age="):
+			seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+			if err == nil && seconds > 0 {
+				age = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	return age
+}
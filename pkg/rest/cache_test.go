@@ -0,0 +1,148 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMaxAge(t *testing.T) {
+	tests := []struct {
+		cacheControl string
+		want         time.Duration
+	}{
+		{cacheControl: "", want: 0},
+		{cacheControl: "public max-age=60", want: time.Minute},
+		{cacheControl: "public, max-age=120", want: 2 * time.Minute},
+		{cacheControl: "max-age=30,public", want: 30 * time.Second},
+		{cacheControl: "Public Max-Age=10", want: 10 * time.Second},
+		{cacheControl: "public max-age=60 no-store", want: 0},
+		{cacheControl: "no-cache, max-age=60", want: 0},
+		{cacheControl: "max-age=0", want: 0},
+		{cacheControl: "max-age=-5", want: 0},
+		{cacheControl: "max-age=soon", want: 0},
+		{cacheControl: "public", want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cacheControl, func(t *testing.T) {
+			if got := maxAge(tt.cacheControl); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLRUCacheExpiry(t *testing.T) {
+	cache := NewLRUCache(10)
+	cache.Set("short", []byte("a"), 20*time.Millisecond)
+	cache.Set("long", []byte("b"), time.Minute)
+	cache.Set("none", []byte("c"), 0)
+
+	if body, ok := cache.Get("short"); !ok || string(body) != "a" {
+		t.Fatalf("got (%q, %v), want the cached body", body, ok)
+	}
+	if _, ok := cache.Get("none"); ok {
+		t.Error("got a body cached without a time to live")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := cache.Get("short"); ok {
+		t.Error("got a body after it expired")
+	}
+	if _, ok := cache.Get("long"); !ok {
+		t.Error("did not get a body that has not expired")
+	}
+	if got := cache.Len(); got != 1 {
+		t.Errorf("cache holds %d responses, want 1 as the expired one is removed", got)
+	}
+	if got, want := cache.Stats(), (CacheStats{Hits: 2, Misses: 2}); got != want {
+		t.Errorf("got stats %+v, want %+v", got, want)
+	}
+}
+
+func TestLRUCacheEviction(t *testing.T) {
+	cache := NewLRUCache(2)
+	cache.Set("a", []byte("a"), time.Minute)
+	cache.Set("b", []byte("b"), time.Minute)
+
+	// Using a makes b the least recently used, so adding c evicts b
+	cache.Get("a")
+	cache.Set("c", []byte("c"), time.Minute)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("got the least recently used response, want it evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("did not get %q, want it kept", key)
+		}
+	}
+
+	// Setting an existing key replaces it without evicting anything
+	cache.Set("a", []byte("new"), time.Minute)
+	if body, _ := cache.Get("a"); string(body) != "new" {
+		t.Errorf("got %q, want the replaced body", body)
+	}
+	if got := cache.Len(); got != 2 {
+		t.Errorf("cache holds %d responses, want 2", got)
+	}
+}
+
+// TestLRUCacheConcurrent uses the cache from many goroutines, and is meant to be run
+// with -race.
+func TestLRUCacheConcurrent(t *testing.T) {
+	cache := NewLRUCache(16)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				key := fmt.Sprintf("key%d", (i+j)%32)
+				if _, ok := cache.Get(key); !ok {
+					cache.Set(key, []byte(key), time.Minute)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got := cache.Len(); got > 16 {
+		t.Errorf("cache holds %d responses, want at most 16", got)
+	}
+}
+
+func TestCacheOverHTTP(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path == "/cached" {
+			w.Header().Set("Cache-Control", "public max-age=60")
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	cache := NewLRUCache(10)
+	client := NewClient(nil, nil, WithCache(cache))
+	for i := 0; i < 3; i++ {
+		if _, err := client.Get(server.URL + "/cached"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.Get(server.URL + "/uncached"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The cached response is fetched once, and the uncached one every time
+	if got := requests.Load(); got != 4 {
+		t.Errorf("server received %d requests, want 4", got)
+	}
+	if got, want := cache.Stats(), (CacheStats{Hits: 2, Misses: 4}); got != want {
+		t.Errorf("got stats %+v, want %+v", got, want)
+	}
+}
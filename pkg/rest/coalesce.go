@@ -0,0 +1,95 @@
+package rest
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Coalescer merges identical GET requests that are in progress at the same time, so
+// that they share a single round trip to the server and its response. Requests are only
+// merged if they are for the same URL and use the same Authorization header, so that
+// an error caused by one token is never returned for a request made with another.
+type Coalescer struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// flight is a request shared by the callers waiting for its response
+type flight struct {
+	done    chan struct{}
+	body    []byte
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// NewCoalescer creates a new coalescer
+func NewCoalescer() *Coalescer {
+	return &Coalescer{}
+}
+
+// WithCoalescing sets the coalescer used to merge identical requests. By default, each
+// client has its own coalescer; a nil coalescer turns coalescing off.
+func WithCoalescing(coalescer *Coalescer) Option {
+	return func(c *client) {
+		c.coalescer = coalescer
+		c.coalescerSet = true
+	}
+}
+
+// do calls fetch once for all the callers that ask for the same key at the same time.
+// Each caller stops waiting for the response when its own context is done, and the
+// shared call is canceled once every caller has stopped waiting for it.
+func (co *Coalescer) do(ctx context.Context, key string, fetch func(context.Context) ([]byte, error)) ([]byte, error) {
+	co.mu.Lock()
+	if co.flights == nil {
+		co.flights = make(map[string]*flight)
+	}
+	f, shared := co.flights[key]
+	if shared {
+		f.waiters++
+	} else {
+		fctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		f = &flight{done: make(chan struct{}), waiters: 1, cancel: cancel}
+		co.flights[key] = f
+		go func() {
+			defer close(f.done)
+			defer cancel()
+			f.body, f.err = fetch(fctx)
+			co.forget(key, f)
+		}()
+	}
+	co.mu.Unlock()
+
+	select {
+	case <-f.done:
+		trace.SpanFromContext(ctx).SetAttributes(attribute.Bool("coc.coalesced", shared))
+		return f.body, f.err
+	case <-ctx.Done():
+		co.mu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			f.cancel()
+			co.forgetLocked(key, f)
+		}
+		co.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// forget stops new callers from joining the flight
+func (co *Coalescer) forget(key string, f *flight) {
+	co.mu.Lock()
+	defer co.mu.Unlock()
+	co.forgetLocked(key, f)
+}
+
+// forgetLocked stops new callers from joining the flight. The caller must hold co.mu.
+func (co *Coalescer) forgetLocked(key string, f *flight) {
+	if co.flights[key] == f {
+		delete(co.flights, key)
+	}
+}
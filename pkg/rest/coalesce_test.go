@@ -0,0 +1,154 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newSlowServer starts a server that holds each request for a while before answering,
+// so that concurrent requests overlap. Requests using the token "bad" are rejected.
+func newSlowServer(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		if r.Header.Get("Authorization") == "Bearer bad" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"reason":"accessDenied.invalidIp"}`))
+			return
+		}
+		w.Write([]byte(`{"tag":"#ABC"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCoalescerMergesIdenticalRequests(t *testing.T) {
+	var requests atomic.Int32
+	server := newSlowServer(t, &requests)
+	client := NewClient(Headers{"Authorization": "Bearer good"}, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Get(server.URL + "/clans/%23ABC"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("server received %d requests, want 1", got)
+	}
+}
+
+func TestCoalescerKeepsTokensApart(t *testing.T) {
+	var requests atomic.Int32
+	server := newSlowServer(t, &requests)
+	coalescer := NewCoalescer()
+	good := NewClient(Headers{"Authorization": "Bearer good"}, nil, WithCoalescing(coalescer))
+	bad := NewClient(Headers{"Authorization": "Bearer bad"}, nil, WithCoalescing(coalescer))
+
+	var wg sync.WaitGroup
+	var goodErr, badErr error
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, badErr = bad.Get(server.URL + "/clans/%23ABC")
+	}()
+	go func() {
+		defer wg.Done()
+		time.Sleep(10 * time.Millisecond)
+		_, goodErr = good.Get(server.URL + "/clans/%23ABC")
+	}()
+	wg.Wait()
+
+	if !errors.Is(badErr, ErrInvalidIP) {
+		t.Errorf("got %v for the bad token, want ErrInvalidIP", badErr)
+	}
+	if goodErr != nil {
+		t.Errorf("got %v for the good token, want the request to succeed", goodErr)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("server received %d requests, want 2", got)
+	}
+}
+
+func TestCoalescerCancelsWhenAllCallersLeave(t *testing.T) {
+	var requests atomic.Int32
+	canceled := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		select {
+		case <-r.Context().Done():
+			close(canceled)
+		case <-time.After(2 * time.Second):
+		}
+	}))
+	t.Cleanup(server.Close)
+	client := NewClient(Headers{"Authorization": "Bearer good"}, nil, WithRetryPolicy(DefaultRetryPolicy()))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(i+1)*20*time.Millisecond)
+			defer cancel()
+			if _, err := client.GetContext(ctx, server.URL+"/clans/%23ABC"); !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("got %v, want the context's error", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("the server request was not canceled after every caller left")
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("server received %d requests, want 1", got)
+	}
+}
+
+func TestCoalescerKeepsRequestForRemainingCallers(t *testing.T) {
+	var requests atomic.Int32
+	server := newSlowServer(t, &requests)
+	client := NewClient(Headers{"Authorization": "Bearer good"}, nil)
+
+	var wg sync.WaitGroup
+	var leftErr, stayedErr error
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, leftErr = client.GetContext(ctx, server.URL+"/clans/%23ABC")
+	}()
+	go func() {
+		defer wg.Done()
+		time.Sleep(5 * time.Millisecond)
+		_, stayedErr = client.Get(server.URL + "/clans/%23ABC")
+	}()
+	wg.Wait()
+
+	if !errors.Is(leftErr, context.DeadlineExceeded) {
+		t.Errorf("got %v for the caller that left, want the context's error", leftErr)
+	}
+	if stayedErr != nil {
+		t.Errorf("got %v for the caller still waiting, want the request to succeed", stayedErr)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("server received %d requests, want 1", got)
+	}
+}
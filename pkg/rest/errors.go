@@ -1,12 +1,76 @@
 package rest
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
 
+var (
+	// ErrRateLimited is returned when a request would exceed the client-side rate limit
+	ErrRateLimited = errors.New("rate limit exceeded")
+
+	// ErrNotFound matches an ErrHttp for a resource that does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrInvalidIP matches an ErrHttp for a token that may not be used from this IP address
+	ErrInvalidIP = errors.New("invalid IP address for token")
+	// ErrInvalidToken matches an ErrHttp for a missing or invalid token
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrThrottled matches an ErrHttp for a request that was throttled by the server
+	ErrThrottled = errors.New("request throttled")
+	// ErrMaintenance matches an ErrHttp returned while the server is in maintenance
+	ErrMaintenance = errors.New("server in maintenance")
+)
+
+// Reasons returned by the Clash of Clans API in the body of an error response
+const (
+	ReasonNotFound         = "notFound"
+	ReasonAccessDenied     = "accessDenied"
+	ReasonInvalidIP        = "accessDenied.invalidIp"
+	ReasonThrottled        = "requestThrottled"
+	ReasonInMaintenance    = "inMaintenance"
+	ReasonBadRequest       = "badRequest"
+	ReasonUnknownException = "unknownException"
+)
+
+// ErrHttp is returned when the server responds with an error status code. The
+// reason and message are decoded from the body of the response, if present.
 type ErrHttp struct {
-	StatusCode int
-	Status     string
+	StatusCode int    `json:"-"`
+	Status     string `json:"-"`
+	// Reason is the machine-readable reason for the error, such as "notFound"
+	Reason string `json:"reason"`
+	// Message is the human-readable description of the error
+	Message string `json:"message"`
+	// RetryAfter is the delay requested by the server's Retry-After header, if any
+	RetryAfter time.Duration `json:"-"`
 }
 
 func (err ErrHttp) Error() string {
-	return fmt.Sprintf("HTTP error: status=%d, reason=%s", err.StatusCode, err.Status)
+	if err.Reason == "" {
+		return fmt.Sprintf("HTTP error: status=%d, reason=%s", err.StatusCode, err.Status)
+	}
+	if err.Message == "" {
+		return fmt.Sprintf("HTTP error: status=%d, reason=%s", err.StatusCode, err.Reason)
+	}
+	return fmt.Sprintf("HTTP error: status=%d, reason=%s, message=%s", err.StatusCode, err.Reason, err.Message)
+}
+
+// Is reports whether the error matches one of the sentinel errors in this package, so
+// the error may be checked using errors.Is.
+func (err ErrHttp) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return err.StatusCode == http.StatusNotFound || err.Reason == ReasonNotFound
+	case ErrInvalidIP:
+		return err.Reason == ReasonInvalidIP
+	case ErrInvalidToken:
+		return err.StatusCode == http.StatusForbidden && err.Reason != ReasonInvalidIP
+	case ErrThrottled:
+		return err.StatusCode == http.StatusTooManyRequests || err.Reason == ReasonThrottled
+	case ErrMaintenance:
+		return err.StatusCode == http.StatusServiceUnavailable || err.Reason == ReasonInMaintenance
+	}
+	return false
 }
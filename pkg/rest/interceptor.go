@@ -0,0 +1,77 @@
+package rest
+
+import (
+	"net/http"
+)
+
+// Interceptor is a hook into every request sent by a client. Interceptors may be used
+// to add headers, audit requests or record metrics. If a request is retried, the
+// interceptors are called for each attempt.
+type Interceptor interface {
+	// BeforeRequest is called before the request is sent. Returning an error stops the
+	// request from being sent.
+	BeforeRequest(req *http.Request) error
+	// AfterResponse is called after a response is received from the server, before its
+	// status is checked. The response body must not be consumed unless it is replaced.
+	// Returning an error fails the request.
+	AfterResponse(req *http.Request, resp *http.Response) error
+}
+
+// InterceptorFuncs is an Interceptor built from a pair of functions, either of which
+// may be nil.
+type InterceptorFuncs struct {
+	Before func(req *http.Request) error
+	After  func(req *http.Request, resp *http.Response) error
+}
+
+// BeforeRequest calls the Before function, if there is one
+func (f InterceptorFuncs) BeforeRequest(req *http.Request) error {
+	if f.Before == nil {
+		return nil
+	}
+	return f.Before(req)
+}
+
+// AfterResponse calls the After function, if there is one
+func (f InterceptorFuncs) AfterResponse(req *http.Request, resp *http.Response) error {
+	if f.After == nil {
+		return nil
+	}
+	return f.After(req, resp)
+}
+
+// interceptorError is returned when an interceptor fails a request. Such requests are
+// never retried.
+type interceptorError struct {
+	err error
+}
+
+func (err interceptorError) Error() string {
+	return err.err.Error()
+}
+
+func (err interceptorError) Unwrap() error {
+	return err.err
+}
+
+// beforeRequest calls the interceptors, in the order they were added, before a request
+// is sent.
+func (c *client) beforeRequest(req *http.Request) error {
+	for _, interceptor := range c.interceptors {
+		if err := interceptor.BeforeRequest(req); err != nil {
+			return interceptorError{err: err}
+		}
+	}
+	return nil
+}
+
+// afterResponse calls the interceptors, in the reverse order they were added, after a
+// response is received.
+func (c *client) afterResponse(req *http.Request, resp *http.Response) error {
+	for i := len(c.interceptors) - 1; i >= 0; i-- {
+		if err := c.interceptors[i].AfterResponse(req, resp); err != nil {
+			return interceptorError{err: err}
+		}
+	}
+	return nil
+}
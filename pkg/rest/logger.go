@@ -0,0 +1,54 @@
+package rest
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// Logger is a structured logger. Each message is followed by alternating keys and
+// values, in the same way as log/slog. A *slog.Logger satisfies this interface.
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// NewSlogLogger returns a Logger that writes to the slog logger. If the slog logger
+// is nil, the default slog logger is used.
+func NewSlogLogger(logger *slog.Logger) Logger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return logger
+}
+
+// NopLogger is a Logger that discards all messages. It is the default logger.
+type NopLogger struct{}
+
+// Debug discards the message
+func (NopLogger) Debug(msg string, args ...interface{}) {}
+
+// Info discards the message
+func (NopLogger) Info(msg string, args ...interface{}) {}
+
+// Warn discards the message
+func (NopLogger) Warn(msg string, args ...interface{}) {}
+
+// Error discards the message
+func (NopLogger) Error(msg string, args ...interface{}) {}
+
+// RedactHeaders returns a copy of the headers that is safe to log, with the token in
+// the Authorization header replaced.
+func RedactHeaders(header http.Header) http.Header {
+	redacted := header.Clone()
+	if auth := redacted.Get("Authorization"); auth != "" {
+		scheme := "Bearer"
+		if i := strings.IndexByte(auth, ' '); i > 0 {
+			scheme = auth[:i]
+		}
+		redacted.Set("Authorization", scheme+" [REDACTED]")
+	}
+	return redacted
+}
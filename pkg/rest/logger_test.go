@@ -0,0 +1,60 @@
+package rest
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRedactHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer secret-token")
+	header.Set("Accept", "application/json")
+
+	redacted := RedactHeaders(header)
+	if got, want := redacted.Get("Authorization"), "Bearer [REDACTED]"; got != want {
+		t.Errorf("got Authorization %q, want %q", got, want)
+	}
+	if got, want := redacted.Get("Accept"), "application/json"; got != want {
+		t.Errorf("got Accept %q, want %q", got, want)
+	}
+	if got, want := header.Get("Authorization"), "Bearer secret-token"; got != want {
+		t.Errorf("original header changed to %q, want %q", got, want)
+	}
+}
+
+func TestLoggerRedactsToken(t *testing.T) {
+	const token = "eyJ0eXAiOiJKV1QiLCJhbGciOiJIUzUxMiJ9.secret"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"reason":"notFound"}`))
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := NewClient(Headers{"Authorization": "Bearer " + token}, nil, WithLogger(NewSlogLogger(logger)))
+
+	if _, err := client.Get(server.URL + "/found"); err != nil {
+		t.Fatal(err)
+	}
+	client.Get(server.URL + "/missing")
+
+	output := buf.String()
+	if !strings.Contains(output, "sending request") {
+		t.Fatalf("got log output %q, want the request to be logged", output)
+	}
+	if strings.Contains(output, token) || strings.Contains(output, "secret") {
+		t.Errorf("got log output %q, want the token left out", output)
+	}
+	if !strings.Contains(output, "Bearer [REDACTED]") {
+		t.Errorf("got log output %q, want the redacted Authorization header", output)
+	}
+}
@@ -0,0 +1,46 @@
+package rest
+
+import (
+	"context"
+	"time"
+)
+
+// Metrics records measurements of the requests sent by a client. Each measurement is
+// labelled with the endpoint template of the request, such as "/clans/{clanTag}".
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// ObserveRequest records a single attempt to send a request. The status code is
+	// zero if no response was received.
+	ObserveRequest(endpoint string, statusCode int, duration time.Duration)
+	// ObserveRetry records that a request is being retried
+	ObserveRetry(endpoint string)
+	// ObserveCache records whether a request was served from the cache
+	ObserveCache(endpoint string, hit bool)
+	// ObserveRateLimitWait records how long a request waited for the rate limiter
+	ObserveRateLimitWait(endpoint string, wait time.Duration)
+}
+
+// endpointKey is the context key for the endpoint template of a request
+type endpointKey struct{}
+
+// WithEndpoint returns a copy of the context that carries the endpoint template, such
+// as "/clans/{clanTag}/members", of the request it is used for. The template is used
+// to label metrics, so that requests for different tags are grouped together.
+func WithEndpoint(ctx context.Context, endpoint string) context.Context {
+	return context.WithValue(ctx, endpointKey{}, endpoint)
+}
+
+// EndpointFromContext returns the endpoint template carried by the context, if any
+func EndpointFromContext(ctx context.Context) (string, bool) {
+	endpoint, ok := ctx.Value(endpointKey{}).(string)
+	return endpoint, ok
+}
+
+// endpoint returns the endpoint template used to label metrics for a request. If the
+// context does not carry one, the path of the request URL is used.
+func endpoint(ctx context.Context, path string) string {
+	if endpoint, ok := EndpointFromContext(ctx); ok {
+		return endpoint
+	}
+	return path
+}
@@ -0,0 +1,110 @@
+package rest
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// LimitPolicy determines what the rate limiter does when a request would exceed the
+// rate limit.
+type LimitPolicy int
+
+const (
+	// Block waits until the request may be sent without exceeding the rate limit
+	Block LimitPolicy = iota
+	// FailFast returns ErrRateLimited instead of waiting
+	FailFast
+)
+
+// RateLimiter is a client-side token bucket rate limiter. A separate bucket is kept
+// for each token, so requests made with different API tokens do not limit each other.
+// A RateLimiter is safe for concurrent use and may be shared across clients.
+type RateLimiter struct {
+	rate    float64
+	burst   int
+	policy  LimitPolicy
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// bucket holds the available tokens for a single API token
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a rate limiter that allows requestsPerSecond requests per
+// second for each token, with bursts of up to burst requests.
+func NewRateLimiter(requestsPerSecond float64, burst int, policy LimitPolicy) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:    requestsPerSecond,
+		burst:   burst,
+		policy:  policy,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Wait blocks until a request using the given key may be sent, and returns how long
+// it waited. With the FailFast policy, ErrRateLimited is returned rather than waiting.
+// If the context is done before the request may be sent, the context's error is
+// returned.
+func (l *RateLimiter) Wait(ctx context.Context, key string) (time.Duration, error) {
+	delay, err := l.reserve(key)
+	if err != nil || delay == 0 {
+		return 0, err
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return delay, nil
+	case <-ctx.Done():
+		l.cancel(key)
+		return 0, ctx.Err()
+	}
+}
+
+// reserve takes a token from the key's bucket and returns how long the caller must
+// wait before the token may be used.
+func (l *RateLimiter) reserve(key string) (time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.burst), last: now}
+		l.buckets[key] = b
+	}
+
+	// Refill the bucket for the time that has passed since it was last used
+	b.tokens += now.Sub(b.last).Seconds() * l.rate
+	if b.tokens > float64(l.burst) {
+		b.tokens = float64(l.burst)
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, nil
+	}
+	if l.policy == FailFast || l.rate <= 0 {
+		return 0, ErrRateLimited
+	}
+	b.tokens--
+	return time.Duration(-b.tokens / l.rate * float64(time.Second)), nil
+}
+
+// cancel returns a reserved token to the key's bucket
+func (l *RateLimiter) cancel(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if b, ok := l.buckets[key]; ok {
+		b.tokens++
+	}
+}
@@ -0,0 +1,151 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterFailFast(t *testing.T) {
+	limiter := NewRateLimiter(1, 2, FailFast)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if wait, err := limiter.Wait(ctx, "a"); err != nil || wait != 0 {
+			t.Fatalf("request %d: got (%s, %v), want it to be sent at once", i+1, wait, err)
+		}
+	}
+	if _, err := limiter.Wait(ctx, "a"); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("got %v once the burst is used, want ErrRateLimited", err)
+	}
+
+	// Each key has its own bucket
+	if _, err := limiter.Wait(ctx, "b"); err != nil {
+		t.Fatalf("got %v for another key, want it to be sent", err)
+	}
+}
+
+func TestRateLimiterRefills(t *testing.T) {
+	limiter := NewRateLimiter(100, 1, FailFast)
+	ctx := context.Background()
+
+	if _, err := limiter.Wait(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := limiter.Wait(ctx, "a"); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("got %v, want ErrRateLimited", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, err := limiter.Wait(ctx, "a"); err != nil {
+		t.Fatalf("got %v after the bucket refilled, want the request to be sent", err)
+	}
+}
+
+func TestRateLimiterBlock(t *testing.T) {
+	limiter := NewRateLimiter(20, 1, Block)
+	ctx := context.Background()
+
+	if wait, err := limiter.Wait(ctx, "a"); err != nil || wait != 0 {
+		t.Fatalf("got (%s, %v), want the first request to be sent at once", wait, err)
+	}
+	start := time.Now()
+	wait, err := limiter.Wait(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wait < 40*time.Millisecond || wait > 50*time.Millisecond {
+		t.Errorf("got a wait of %s, want about 50ms", wait)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("returned after %s, want it to block for about 50ms", elapsed)
+	}
+}
+
+func TestRateLimiterBlockWithoutRate(t *testing.T) {
+	limiter := NewRateLimiter(0, 0, Block)
+	ctx := context.Background()
+
+	if _, err := limiter.Wait(ctx, "a"); err != nil {
+		t.Fatalf("got %v, want a burst of 1", err)
+	}
+	if _, err := limiter.Wait(ctx, "a"); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("got %v, want ErrRateLimited as the bucket never refills", err)
+	}
+}
+
+func TestRateLimiterCanceledWaitReturnsToken(t *testing.T) {
+	limiter := NewRateLimiter(1, 1, Block)
+
+	if _, err := limiter.Wait(context.Background(), "a"); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.Wait(ctx, "a"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want the context's error", err)
+	}
+
+	// Only the first request's token is spent, so the next one waits less than a second
+	delay, err := limiter.reserve("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delay > time.Second {
+		t.Errorf("got a delay of %s, want at most 1s as the canceled wait returned its token", delay)
+	}
+}
+
+// TestRateLimiterConcurrent waits on the limiter from many goroutines, and is meant to
+// be run with -race.
+func TestRateLimiterConcurrent(t *testing.T) {
+	limiter := NewRateLimiter(100, 5, Block)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := limiter.Wait(context.Background(), "a"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 5 requests are sent at once, and the other 15 at 100 per second
+	if elapsed := time.Since(start); elapsed < 140*time.Millisecond {
+		t.Errorf("20 requests took %s, want at least 150ms", elapsed)
+	}
+}
+
+func TestRateLimiterOverHTTP(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	limiter := NewRateLimiter(1, 1, FailFast)
+	client := NewClient(Headers{"Authorization": "Bearer a"}, nil, WithRateLimiter(limiter))
+	other := NewClient(Headers{"Authorization": "Bearer b"}, nil, WithRateLimiter(limiter))
+
+	if _, err := client.Get(server.URL + "/first"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Get(server.URL + "/second"); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("got %v, want ErrRateLimited", err)
+	}
+	if _, err := other.Get(server.URL + "/second"); err != nil {
+		t.Fatalf("got %v for another token, want the request to be sent", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("server received %d requests, want 2", got)
+	}
+}
@@ -1,21 +1,36 @@
 package rest
 
 import (
-	"crypto/tls"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
+	"time"
 
-	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// maxErrorBodySize is the most that is read from the body of an error response
+	maxErrorBodySize = 64 * 1024
+	// tracerName is the name of the tracer used to create spans
+	tracerName = "github.com/clashgolang/coc/pkg/rest"
 )
 
 var (
-	// HTTP transport that disables checking of the TLS certificate
-	tr = &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-	}
+	// defaultTransport is shared by all clients that are not given an HTTP client or
+	// transport, so that connections to the server are reused
+	defaultTransport = NewTransport(DefaultTransportConfig())
 )
 
 // QParms are the optional query parameters to include on a HTTP request
@@ -32,18 +47,157 @@ type Client interface {
 	QParms() QParms
 	// Get sends a GET request to the HTTP server
 	Get(url string) ([]byte, error)
+	// GetContext sends a GET request to the HTTP server, using the context to
+	// cancel the request
+	GetContext(ctx context.Context, url string) ([]byte, error)
+	// Post sends a POST request with a JSON body to the HTTP server
+	Post(url string, body interface{}) ([]byte, error)
+	// PostContext sends a POST request with a JSON body to the HTTP server, using
+	// the context to cancel the request
+	PostContext(ctx context.Context, url string, body interface{}) ([]byte, error)
+	// WithQParms returns a copy of the client that includes the given query
+	// parameters on its requests. The copy shares the connections, rate limiter,
+	// retry policy, cache and coalescer of the original client.
+	WithQParms(qparms QParms) Client
+}
+
+// Option configures an optional setting on a REST client
+type Option func(*client)
+
+// WithHTTPClient sets the HTTP client used to send requests to the server. This may
+// be used to set a custom CA pool, proxy or timeouts. By default, a client using a
+// transport built by NewTransport with DefaultTransportConfig, which verifies TLS
+// certificates, is used. That transport is shared by all clients created without an
+// HTTP client or transport, so that connections to the server are reused.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTransport sets the transport used to send requests to the server. If an HTTP
+// client is also provided, the transport replaces the one used by that client.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *client) {
+		c.transport = transport
+	}
+}
+
+// WithTransportConfig sets the connection pool settings of the client's transport. It
+// has no effect if an HTTP client or transport is also provided.
+func WithTransportConfig(config TransportConfig) Option {
+	return func(c *client) {
+		c.transportConfig = &config
+	}
+}
+
+// WithLogger sets the logger used by the REST client. By default, nothing is logged.
+func WithLogger(logger Logger) Option {
+	return func(c *client) {
+		c.log = logger
+	}
+}
+
+// WithRateLimiter sets the rate limiter used to throttle requests sent by the client.
+// Requests are limited per Authorization header, so each API token has its own limit.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *client) {
+		c.limiter = limiter
+	}
+}
+
+// WithRetryPolicy sets the policy used to retry requests that fail with a transient error
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *client) {
+		c.retry = &policy
+	}
+}
+
+// WithCache sets the cache used to store responses. Responses are cached for the
+// max-age given in their Cache-Control header.
+func WithCache(cache Cache) Option {
+	return func(c *client) {
+		c.cache = cache
+	}
+}
+
+// WithInterceptors adds interceptors that are called before each request is sent and
+// after each response is received. The interceptors are called before requests in the
+// order they are added, and after responses in the reverse order.
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(c *client) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+// WithMetrics sets where measurements of the requests sent by the client are recorded
+func WithMetrics(metrics Metrics) Option {
+	return func(c *client) {
+		c.metrics = metrics
+	}
+}
+
+// WithTracerProvider sets the provider of the tracer used to create a span for each
+// attempt to send a request. By default, the global tracer provider is used.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *client) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithCircuitBreaker sets the circuit breaker used to stop sending requests while the
+// server is failing or in maintenance
+func WithCircuitBreaker(breaker *CircuitBreaker) Option {
+	return func(c *client) {
+		c.breaker = breaker
+	}
 }
 
 // NewClient creates a new REST client
-func NewClient(headers Headers, qparms QParms) Client {
-	c := &client{headers: headers, qparms: qparms}
+func NewClient(headers Headers, qparms QParms, options ...Option) Client {
+	c := &client{headers: headers, qparms: qparms, log: NopLogger{}}
+	for _, option := range options {
+		option(c)
+	}
+	if c.tracerProvider == nil {
+		c.tracerProvider = otel.GetTracerProvider()
+	}
+	c.tracer = c.tracerProvider.Tracer(tracerName)
+	if !c.coalescerSet {
+		c.coalescer = NewCoalescer()
+	}
+	if c.httpClient == nil {
+		c.httpClient = &http.Client{Transport: defaultTransport}
+		if c.transport == nil && c.transportConfig != nil {
+			c.httpClient.Transport = NewTransport(*c.transportConfig)
+		}
+	}
+	if c.transport != nil {
+		httpClient := *c.httpClient
+		httpClient.Transport = c.transport
+		c.httpClient = &httpClient
+	}
 	return c
 }
 
 // Client is the HTTP client used to send the request to a server.
 type client struct {
-	headers Headers
-	qparms  QParms
+	headers         Headers
+	qparms          QParms
+	httpClient      *http.Client
+	transport       http.RoundTripper
+	transportConfig *TransportConfig
+	limiter         *RateLimiter
+	retry           *RetryPolicy
+	cache           Cache
+	interceptors    []Interceptor
+	metrics         Metrics
+	tracerProvider  trace.TracerProvider
+	tracer          trace.Tracer
+	breaker         *CircuitBreaker
+	coalescer       *Coalescer
+	coalescerSet    bool
+	log             Logger
 }
 
 // Headers retrieves the optional headers to include on the REST request
@@ -56,37 +210,105 @@ func (c *client) QParms() QParms {
 	return c.qparms
 }
 
+// WithQParms returns a copy of the client that includes the given query parameters
+// on its requests
+func (c *client) WithQParms(qparms QParms) Client {
+	clone := *c
+	clone.qparms = qparms
+	return &clone
+}
+
 // Get sends a GET request to the HTTP server
 func (c *client) Get(url string) ([]byte, error) {
-	const M = "rest.Client.Get"
-	log.Debug(M, " -->")
-	defer log.Debug(M, " <--")
+	return c.GetContext(context.Background(), url)
+}
 
+// GetContext sends a GET request to the HTTP server. If the context is canceled
+// or its deadline is exceeded, the context's error is returned.
+func (c *client) GetContext(ctx context.Context, url string) ([]byte, error) {
+	req, urlWithQparms, err := c.newRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Serve the response from the cache, if it is there
+	ep := endpoint(ctx, req.URL.Path)
+	if c.cache != nil {
+		body, ok := c.cache.Get(urlWithQparms)
+		if c.metrics != nil {
+			c.metrics.ObserveCache(ep, ok)
+		}
+		trace.SpanFromContext(ctx).SetAttributes(attribute.Bool("coc.cache_hit", ok))
+		if ok {
+			c.log.Debug("response served from the cache", "url", urlWithQparms)
+			return body, nil
+		}
+	}
+
+	// Send the request, sharing the response with identical requests in progress
+	if c.coalescer == nil {
+		return c.fetch(ctx, ep, urlWithQparms, req)
+	}
+	key := req.Header.Get("Authorization") + " " + urlWithQparms
+	return c.coalescer.do(ctx, key, func(ctx context.Context) ([]byte, error) {
+		return c.fetch(ctx, ep, urlWithQparms, req.WithContext(ctx))
+	})
+}
+
+// Post sends a POST request with a JSON body to the HTTP server
+func (c *client) Post(url string, body interface{}) ([]byte, error) {
+	return c.PostContext(context.Background(), url, body)
+}
+
+// PostContext sends a POST request to the HTTP server, with the body encoded as JSON.
+// The request is retried in the same way as a GET request, so it should only be used
+// for requests that are safe to repeat. Responses to POST requests are neither cached
+// nor shared with other requests.
+func (c *client) PostContext(ctx context.Context, url string, body interface{}) ([]byte, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		c.log.Error("failed to encode the request body", "url", url, "error", err)
+		return nil, err
+	}
+	req, urlWithQparms, err := c.newRequest(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return c.fetch(ctx, endpoint(ctx, req.URL.Path), urlWithQparms, req)
+}
+
+// newRequest creates an HTTP request for the URL, with the client's query parameters
+// and headers added to it. The URL with the query parameters is also returned.
+func (c *client) newRequest(ctx context.Context, method string, url string, body io.Reader) (*http.Request, string, error) {
 	// Add any query paramegters to the URL
 	var sb strings.Builder
 	sb.Grow(100)
 	sb.WriteString(url)
 	if c.QParms() != nil {
-		first := true
-		for k, v := range c.QParms() {
-			if first {
+		// Sort the keys so the same parameters always produce the same URL
+		keys := make([]string, 0, len(c.QParms()))
+		for k := range c.QParms() {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i == 0 {
 				sb.WriteString("?")
-				first = false
 			} else {
 				sb.WriteString("&")
 			}
-			sb.WriteString(fmt.Sprintf("%s=%v", k, escapeString(v)))
+			sb.WriteString(fmt.Sprintf("%s=%v", k, escapeString(c.QParms()[k])))
 		}
 	}
 	urlWithQparms := sb.String()
-	log.Trace("url=" + urlWithQparms)
 
 	// Get the http request
-	log.Debug("GET url=", url)
-	req, err := http.NewRequest("GET", urlWithQparms, nil)
+	req, err := http.NewRequestWithContext(ctx, method, urlWithQparms, body)
 	if err != nil {
-		log.Error("failed to get the http request")
-		return nil, err
+		c.log.Error("failed to get the http request", "url", urlWithQparms, "error", err)
+		return nil, "", err
 	}
 
 	// Add any custom headers
@@ -95,33 +317,171 @@ func (c *client) Get(url string) ([]byte, error) {
 			req.Header.Set(k, v)
 		}
 	}
+	return req, urlWithQparms, nil
+}
+
+// fetch sends the request, retrying it if the retry policy allows, and caches the
+// response to a GET request if it succeeds
+func (c *client) fetch(ctx context.Context, ep string, urlWithQparms string, req *http.Request) ([]byte, error) {
+	for attempt := 1; ; attempt++ {
+		body, header, err := c.send(ctx, ep, attempt, req)
+		retry := err != nil && c.retryable(attempt, err)
+		if !retry {
+			trace.SpanFromContext(ctx).SetAttributes(attribute.Int("coc.retry_count", attempt-1))
+		}
+		if err == nil {
+			if c.cache != nil && req.Method == http.MethodGet {
+				c.cache.Set(urlWithQparms, body, maxAge(header.Get("Cache-Control")))
+			}
+			return body, nil
+		}
+		if !retry {
+			return nil, err
+		}
+
+		delay := c.retry.delay(attempt, err)
+		c.log.Debug("retrying request", "url", urlWithQparms, "attempt", attempt+1, "delay", delay, "error", err)
+		if c.metrics != nil {
+			c.metrics.ObserveRetry(ep)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// send sends a single request to the HTTP server in its own trace span, and returns
+// the body and headers of the response. The endpoint template is used to label
+// metrics and spans.
+func (c *client) send(ctx context.Context, ep string, attempt int, req *http.Request) ([]byte, http.Header, error) {
+	ctx, span := c.tracer.Start(ctx, "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("url.full", req.URL.String()),
+			attribute.String("coc.endpoint", ep),
+			attribute.Int("coc.attempt", attempt),
+		),
+	)
+	defer span.End()
+
+	// Fail fast, without contacting the server, while the circuit breaker is open
+	if c.breaker != nil {
+		if err := c.breaker.allow(); err != nil {
+			c.log.Debug("request not sent as the circuit breaker is open", "error", err)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return nil, nil, err
+		}
+	}
+
+	body, header, err := c.sendAttempt(ctx, ep, req)
+	if c.breaker != nil {
+		c.breaker.record(ctx, err)
+	}
+	var httpErr ErrHttp
+	switch {
+	case err == nil:
+		span.SetAttributes(attribute.Int("http.response.status_code", http.StatusOK))
+	case errors.As(err, &httpErr):
+		span.SetAttributes(attribute.Int("http.response.status_code", httpErr.StatusCode))
+		fallthrough
+	default:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return body, header, err
+}
+
+// sendAttempt sends a single request to the HTTP server and returns the body and
+// headers of the response.
+func (c *client) sendAttempt(ctx context.Context, ep string, req *http.Request) ([]byte, http.Header, error) {
+	// Wait until the rate limiter allows the request to be sent
+	if c.limiter != nil {
+		wait, err := c.limiter.Wait(ctx, req.Header.Get("Authorization"))
+		if c.metrics != nil {
+			c.metrics.ObserveRateLimitWait(ep, wait)
+		}
+		if err != nil {
+			c.log.Debug("request not sent due to rate limit", "error", err)
+			return nil, nil, err
+		}
+	}
 
 	// Send the request to Clash of Clans and get the response
-	client := &http.Client{Transport: tr}
-	resp, err := client.Do(req)
+	req = req.Clone(ctx)
+	if req.GetBody != nil {
+		// The body may have been read by an earlier attempt, so start it again
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, nil, err
+		}
+		req.Body = body
+	}
+	if err := c.beforeRequest(req); err != nil {
+		c.log.Debug("request stopped by an interceptor", "error", err)
+		return nil, nil, err
+	}
+	c.log.Debug("sending request", "method", req.Method, "url", req.URL.String(), "headers", RedactHeaders(req.Header))
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		log.Error("failed to send the request to CoC")
-		return nil, err
+		c.observeRequest(ep, 0, start)
+		if ctx.Err() != nil {
+			c.log.Debug("request canceled", "error", ctx.Err())
+			return nil, nil, ctx.Err()
+		}
+		c.log.Error("failed to send the request to CoC", "url", req.URL.String(), "error", err)
+		return nil, nil, err
 	}
 	defer resp.Body.Close()
+	defer c.observeRequest(ep, resp.StatusCode, start)
+	if err := c.afterResponse(req, resp); err != nil {
+		c.log.Debug("response rejected by an interceptor", "error", err)
+		return nil, nil, err
+	}
 
 	// If an error status code was returned by the server, pass the error back to the invoker
 	if resp.StatusCode != 200 {
-		log.Error("failed to send the request to CoC, statusCode=", resp.StatusCode, ", status=", resp.Status)
-		err := ErrHttp{StatusCode: resp.StatusCode, Status: resp.Status}
-		return nil, err
+		err := ErrHttp{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+		if body, readErr := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize)); readErr == nil {
+			if jsonErr := json.Unmarshal(body, &err); jsonErr != nil {
+				c.log.Debug("unable to parse the error response", "error", jsonErr)
+			}
+		}
+		c.log.Error("request to CoC failed", "url", req.URL.String(), "statusCode", err.StatusCode, "reason", err.Reason, "message", err.Message)
+		return nil, nil, err
 	}
 
 	// Read the body
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		log.Error("failed to read the body")
-		return nil, err
+		if ctx.Err() != nil {
+			c.log.Debug("request canceled", "error", ctx.Err())
+			return nil, nil, ctx.Err()
+		}
+		c.log.Error("failed to read the body", "url", req.URL.String(), "error", err)
+		return nil, nil, err
 	}
-	log.Trace("response body=" + string(body))
+	c.log.Debug("received response", "url", req.URL.String(), "statusCode", resp.StatusCode, "size", len(body))
 
 	// All good, so return the response
-	return body, nil
+	return body, resp.Header, nil
+}
+
+// observeRequest records an attempt to send a request that started at the given time
+func (c *client) observeRequest(ep string, statusCode int, start time.Time) {
+	if c.metrics != nil {
+		c.metrics.ObserveRequest(ep, statusCode, time.Since(start))
+	}
 }
 
 // escapeString will escape a string, otherwise it returns the value unchanged
@@ -0,0 +1,119 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy determines which failed requests are retried and how long to wait
+// between attempts. Requests that fail with a 403 or 404 status are never retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a request is sent, including the first
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry; it doubles on each later retry
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between attempts, unless the server asks for a longer
+	// one in a Retry-After header
+	MaxDelay time.Duration
+	// RetryableStatusCodes are the HTTP status codes that cause a request to be retried
+	RetryableStatusCodes []int
+	// RetryNetworkErrors retries requests that failed without a response from the server
+	RetryNetworkErrors bool
+}
+
+// DefaultRetryPolicy returns a retry policy that retries throttled requests, server
+// errors, maintenance and network errors up to three times.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RetryNetworkErrors: true,
+	}
+}
+
+// retryable returns whether a request that failed on the given attempt with the
+// given error should be sent again.
+func (p *RetryPolicy) retryable(attempt int, err error) bool {
+	if attempt >= p.MaxAttempts {
+		return false
+	}
+
+	var httpErr ErrHttp
+	if errors.As(err, &httpErr) {
+		if httpErr.StatusCode == http.StatusForbidden || httpErr.StatusCode == http.StatusNotFound {
+			return false
+		}
+		for _, code := range p.RetryableStatusCodes {
+			if httpErr.StatusCode == code {
+				return true
+			}
+		}
+		return false
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrRateLimited) {
+		return false
+	}
+	if errors.As(err, &interceptorError{}) || errors.As(err, &ErrCircuitOpen{}) {
+		return false
+	}
+	return p.RetryNetworkErrors
+}
+
+// retryable reports whether the client should retry a request that failed on the
+// given attempt. Requests are not retried while the circuit breaker is open, so the
+// error from the server is returned rather than one from the breaker.
+func (c *client) retryable(attempt int, err error) bool {
+	if c.retry == nil || !c.retry.retryable(attempt, err) {
+		return false
+	}
+	return c.breaker == nil || c.breaker.State() != BreakerOpen
+}
+
+// delay returns how long to wait before retrying a request that failed on the given
+// attempt. The server's Retry-After header takes precedence; otherwise the delay
+// grows exponentially with full jitter.
+func (p *RetryPolicy) delay(attempt int, err error) time.Duration {
+	var httpErr ErrHttp
+	if errors.As(err, &httpErr) && httpErr.RetryAfter > 0 {
+		return httpErr.RetryAfter
+	}
+
+	backoff := p.BaseDelay << uint(attempt-1)
+	if backoff <= 0 || (p.MaxDelay > 0 && backoff > p.MaxDelay) {
+		backoff = p.MaxDelay
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(backoff) + 1))
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a number
+// of seconds or an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
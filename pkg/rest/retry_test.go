@@ -0,0 +1,155 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyRetryable(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	noNetwork := DefaultRetryPolicy()
+	noNetwork.RetryNetworkErrors = false
+	listsClientErrors := DefaultRetryPolicy()
+	listsClientErrors.RetryableStatusCodes = append(listsClientErrors.RetryableStatusCodes, http.StatusForbidden, http.StatusNotFound)
+
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		attempt int
+		err     error
+		want    bool
+	}{
+		{name: "service unavailable", policy: policy, attempt: 1, err: ErrHttp{StatusCode: http.StatusServiceUnavailable}, want: true},
+		{name: "throttled", policy: policy, attempt: 1, err: ErrHttp{StatusCode: http.StatusTooManyRequests}, want: true},
+		{name: "server error on a later attempt", policy: policy, attempt: 3, err: ErrHttp{StatusCode: http.StatusInternalServerError}, want: true},
+		{name: "last attempt", policy: policy, attempt: 4, err: ErrHttp{StatusCode: http.StatusServiceUnavailable}, want: false},
+		{name: "bad request", policy: policy, attempt: 1, err: ErrHttp{StatusCode: http.StatusBadRequest}, want: false},
+		{name: "forbidden", policy: policy, attempt: 1, err: ErrHttp{StatusCode: http.StatusForbidden}, want: false},
+		{name: "not found", policy: policy, attempt: 1, err: ErrHttp{StatusCode: http.StatusNotFound}, want: false},
+		{name: "forbidden listed as retryable", policy: listsClientErrors, attempt: 1, err: ErrHttp{StatusCode: http.StatusForbidden}, want: false},
+		{name: "not found listed as retryable", policy: listsClientErrors, attempt: 1, err: ErrHttp{StatusCode: http.StatusNotFound}, want: false},
+		{name: "wrapped server error", policy: policy, attempt: 1, err: fmt.Errorf("get: %w", ErrHttp{StatusCode: http.StatusBadGateway}), want: true},
+		{name: "network error", policy: policy, attempt: 1, err: errors.New("connection reset"), want: true},
+		{name: "network error not retried", policy: noNetwork, attempt: 1, err: errors.New("connection reset"), want: false},
+		{name: "canceled", policy: policy, attempt: 1, err: context.Canceled, want: false},
+		{name: "deadline exceeded", policy: policy, attempt: 1, err: context.DeadlineExceeded, want: false},
+		{name: "client-side rate limit", policy: policy, attempt: 1, err: ErrRateLimited, want: false},
+		{name: "interceptor", policy: policy, attempt: 1, err: interceptorError{err: errors.New("stop")}, want: false},
+		{name: "circuit open", policy: policy, attempt: 1, err: ErrCircuitOpen{Until: time.Now()}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.retryable(tt.attempt, tt.err); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	serverErr := ErrHttp{StatusCode: http.StatusInternalServerError}
+
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		attempt int
+		err     error
+		max     time.Duration
+	}{
+		{name: "first retry", policy: policy, attempt: 1, err: serverErr, max: 100 * time.Millisecond},
+		{name: "second retry", policy: policy, attempt: 2, err: serverErr, max: 200 * time.Millisecond},
+		{name: "fourth retry", policy: policy, attempt: 4, err: serverErr, max: 800 * time.Millisecond},
+		{name: "capped", policy: policy, attempt: 5, err: serverErr, max: time.Second},
+		{name: "capped after overflow", policy: policy, attempt: 70, err: serverErr, max: time.Second},
+		{name: "no delay", policy: RetryPolicy{}, attempt: 1, err: serverErr, max: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				if got := tt.policy.delay(tt.attempt, tt.err); got < 0 || got > tt.max {
+					t.Fatalf("got %s, want a delay between 0 and %s", got, tt.max)
+				}
+			}
+		})
+	}
+
+	t.Run("retry after takes precedence", func(t *testing.T) {
+		err := ErrHttp{StatusCode: http.StatusServiceUnavailable, RetryAfter: 5 * time.Second}
+		if got := policy.delay(1, err); got != 5*time.Second {
+			t.Errorf("got %s, want the 5s requested by the server", got)
+		}
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		min   time.Duration
+		max   time.Duration
+	}{
+		{name: "empty", value: ""},
+		{name: "one second", value: "1", min: time.Second, max: time.Second},
+		{name: "two minutes", value: "120", min: 2 * time.Minute, max: 2 * time.Minute},
+		{name: "zero", value: "0"},
+		{name: "negative", value: "-5"},
+		{name: "not a number or date", value: "soon"},
+		{name: "future date", value: time.Now().Add(90 * time.Second).UTC().Format(http.TimeFormat), min: 85 * time.Second, max: 90 * time.Second},
+		{name: "past date", value: time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value); got < tt.min || got > tt.max {
+				t.Errorf("got %s, want between %s and %s", got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestRetryOverHTTP(t *testing.T) {
+	var maintenance, missing atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/maintenance":
+			if maintenance.Add(1) == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				w.Write([]byte(`{"reason":"inMaintenance"}`))
+				return
+			}
+			w.Write([]byte(`{}`))
+		case "/missing":
+			missing.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"reason":"notFound"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(nil, nil, WithRetryPolicy(DefaultRetryPolicy()))
+
+	start := time.Now()
+	if _, err := client.Get(server.URL + "/maintenance"); err != nil {
+		t.Fatalf("got %v, want the retried request to succeed", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, want the 1s requested by Retry-After", elapsed)
+	}
+	if got := maintenance.Load(); got != 2 {
+		t.Errorf("server received %d requests during maintenance, want 2", got)
+	}
+
+	if _, err := client.Get(server.URL + "/missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got %v, want ErrNotFound", err)
+	}
+	if got := missing.Load(); got != 1 {
+		t.Errorf("server received %d requests for a missing resource, want 1", got)
+	}
+}
@@ -0,0 +1,50 @@
+package rest
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// TransportConfig holds the connection pool settings of an HTTP transport
+type TransportConfig struct {
+	// MaxIdleConns is the maximum number of idle connections across all hosts
+	MaxIdleConns int
+	// MaxIdleConnsPerHost is the maximum number of idle connections kept to each host
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept before it is closed
+	IdleConnTimeout time.Duration
+	// KeepAlive is the interval between TCP keep-alive probes; a negative value
+	// disables them
+	KeepAlive time.Duration
+	// DialTimeout is the maximum time to wait for a connection to be established
+	DialTimeout time.Duration
+}
+
+// DefaultTransportConfig returns connection pool settings suited to sending many
+// concurrent requests to a single API server.
+func DefaultTransportConfig() TransportConfig {
+	return TransportConfig{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 100,
+		IdleConnTimeout:     90 * time.Second,
+		KeepAlive:           30 * time.Second,
+		DialTimeout:         30 * time.Second,
+	}
+}
+
+// NewTransport creates an HTTP transport with the given connection pool settings. All
+// other settings, including TLS certificate verification, are the same as those of
+// http.DefaultTransport.
+func NewTransport(config TransportConfig) *http.Transport {
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	dialer := &net.Dialer{
+		Timeout:   config.DialTimeout,
+		KeepAlive: config.KeepAlive,
+	}
+	tr.DialContext = dialer.DialContext
+	tr.MaxIdleConns = config.MaxIdleConns
+	tr.MaxIdleConnsPerHost = config.MaxIdleConnsPerHost
+	tr.IdleConnTimeout = config.IdleConnTimeout
+	return tr
+}